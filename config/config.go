@@ -5,6 +5,7 @@ import (
 	"log"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -57,6 +58,13 @@ func LoadConfig(path string) IConfig {
 				return p
 			}(),
 			gcpBucket: envMap["APP_GCP_BUCKET"],
+			location: func() *time.Location {
+				loc, err := time.LoadLocation(envMap["APP_TIMEZONE"])
+				if err != nil {
+					log.Fatalf("Error loading timezone: %v", err)
+				}
+				return loc
+			}(),
 		},
 		db: &db{
 			host: envMap["DB_HOST"],
@@ -100,6 +108,57 @@ func LoadConfig(path string) IConfig {
 				return p
 			}(),
 		},
+		scheduler: &scheduler{
+			interval: func() time.Duration {
+				t, err := strconv.Atoi(envMap["SCHEDULER_INTERVAL"])
+				if err != nil {
+					log.Fatalf("Error loading scheduler interval: %v", err)
+				}
+				return time.Duration(int64(t) * int64(math.Pow10(9)))
+			}(),
+			orderExpires: func() time.Duration {
+				t, err := strconv.Atoi(envMap["SCHEDULER_ORDER_EXPIRES"])
+				if err != nil {
+					log.Fatalf("Error loading order expires: %v", err)
+				}
+				return time.Duration(int64(t) * int64(math.Pow10(9)))
+			}(),
+			recommendationsInterval: func() time.Duration {
+				t, err := strconv.Atoi(envMap["SCHEDULER_RECOMMENDATIONS_INTERVAL"])
+				if err != nil {
+					log.Fatalf("Error loading recommendations interval: %v", err)
+				}
+				return time.Duration(int64(t) * int64(math.Pow10(9)))
+			}(),
+		},
+		order: &order{
+			numberFormat: func() string {
+				f := envMap["ORDER_NUMBER_FORMAT"]
+				if !strings.Contains(f, "{SEQ}") {
+					log.Fatalf("Error loading order number format: %q has no {SEQ}", f)
+				}
+				// A yearly sequence repeats, only the year keeps the numbers apart
+				resetYearly, _ := strconv.ParseBool(envMap["ORDER_NUMBER_RESET_YEARLY"])
+				if resetYearly && !strings.Contains(f, "{YYYY}") && !strings.Contains(f, "{YY}") {
+					log.Fatalf("Error loading order number format: %q has no {YYYY} or {YY} but resets yearly", f)
+				}
+				return f
+			}(),
+			numberPadding: func() int {
+				p, err := strconv.Atoi(envMap["ORDER_NUMBER_PADDING"])
+				if err != nil {
+					log.Fatalf("Error loading order number padding: %v", err)
+				}
+				return p
+			}(),
+			numberResetYearly: func() bool {
+				b, err := strconv.ParseBool(envMap["ORDER_NUMBER_RESET_YEARLY"])
+				if err != nil {
+					log.Fatalf("Error loading order number reset yearly: %v", err)
+				}
+				return b
+			}(),
+		},
 	}
 }
 
@@ -107,12 +166,16 @@ type IConfig interface {
 	App() IAppConfig
 	Db() IDbConfig
 	Jwt() IJwtConfig
+	Scheduler() ISchedulerConfig
+	Order() IOrderConfig
 }
 
 type config struct {
-	app *app
-	db  *db
-	jwt *jwt
+	app       *app
+	db        *db
+	jwt       *jwt
+	scheduler *scheduler
+	order     *order
 }
 
 type IAppConfig interface {
@@ -124,6 +187,8 @@ type IAppConfig interface {
 	BodyLimit() int //bytes
 	FileLimit() int //bytes
 	GCPBucket() string
+	TimeZone() string
+	Location() *time.Location
 }
 
 type app struct {
@@ -136,6 +201,7 @@ type app struct {
 	bodyLimit    int //bytes
 	fileLimit    int //bytes
 	gcpBucket    string
+	location     *time.Location
 }
 
 func (c *config) App() IAppConfig {
@@ -149,6 +215,8 @@ func (a *app) WriteTimeout() time.Duration { return a.writeTimeout }
 func (a *app) BodyLimit() int              { return a.bodyLimit }
 func (a *app) FileLimit() int              { return a.fileLimit }
 func (a *app) GCPBucket() string           { return a.gcpBucket }
+func (a *app) TimeZone() string            { return a.location.String() }
+func (a *app) Location() *time.Location    { return a.location }
 
 type IDbConfig interface {
 	Url() string
@@ -206,3 +274,43 @@ func (j *jwt) AccessExpiresAt() int       { return j.accessExpiresAt }
 func (j *jwt) RefreshExpiresAt() int      { return j.refreshExpiresAt }
 func (j *jwt) SetJwtAccessExpires(t int)  { j.accessExpiresAt = t }
 func (j *jwt) SetJwtRefreshExpires(t int) { j.refreshExpiresAt = t }
+
+type ISchedulerConfig interface {
+	Interval() time.Duration
+	OrderExpires() time.Duration            // 0 = never expire
+	RecommendationsInterval() time.Duration // 0 = never refresh
+}
+
+type scheduler struct {
+	interval                time.Duration
+	orderExpires            time.Duration
+	recommendationsInterval time.Duration
+}
+
+func (c *config) Scheduler() ISchedulerConfig {
+	return c.scheduler
+}
+
+func (s *scheduler) Interval() time.Duration                { return s.interval }
+func (s *scheduler) OrderExpires() time.Duration            { return s.orderExpires }
+func (s *scheduler) RecommendationsInterval() time.Duration { return s.recommendationsInterval }
+
+type IOrderConfig interface {
+	NumberFormat() string // {YYYY}, {YY} and {SEQ} are replaced, e.g. BS-{YYYY}-{SEQ}
+	NumberPadding() int
+	NumberResetYearly() bool
+}
+
+type order struct {
+	numberFormat      string
+	numberPadding     int
+	numberResetYearly bool
+}
+
+func (c *config) Order() IOrderConfig {
+	return c.order
+}
+
+func (o *order) NumberFormat() string    { return o.numberFormat }
+func (o *order) NumberPadding() int      { return o.numberPadding }
+func (o *order) NumberResetYearly() bool { return o.numberResetYearly }
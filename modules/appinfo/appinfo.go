@@ -1,17 +1,99 @@
 package appinfo
 
+import (
+	"regexp"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+)
+
 type CategoryFilter struct {
 	Title string `query:"title"`
+	*entities.SortReq
+}
+
+// SortFields are the fields that sort accepts
+var SortFields = map[string]string{
+	"id":       `"id"`,
+	"title":    `"title"`,
+	"position": `"position"`,
 }
 
 type Category struct {
+	Id          int             `db:"id" json:"id"`
+	Title       string          `db:"title" json:"title"`
+	Slug        string          `db:"slug" json:"slug,omitempty"`
+	Position    int             `db:"position" json:"position,omitempty"` // display order, lower comes first
+	Image       *entities.Image `db:"image" json:"image,omitempty"`
+	ParentId    *int            `db:"parent_id" json:"parent_id,omitempty"`
+	Breadcrumbs []*Category     `db:"-" json:"breadcrumbs,omitempty"` // from the root to the category
+	Children    []*Category     `db:"-" json:"children,omitempty"`
+}
+
+// SlugRes is what a slug points to, a moved slug is an old slug of the category
+type SlugRes struct {
 	Id    int    `db:"id" json:"id"`
-	Title string `db:"title" json:"title"`
+	Slug  string `db:"slug" json:"slug"` // the current slug
+	Moved bool   `db:"moved" json:"-"`
+}
+
+// AttributeNameRegexp is the format of an attribute name, it is used as the
+// key of the product values and of the attr.<name> filter
+var AttributeNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// AttributeTypes are the types of attribute values, a select value is one of
+// the allowed values
+var AttributeTypes = map[string]bool{
+	"text":    true,
+	"number":  true,
+	"boolean": true,
+	"select":  true,
+}
+
+// Attribute defines a spec of the products of the category and its descendants
+type Attribute struct {
+	Id         string   `json:"id"`
+	CategoryId int      `json:"category_id"` // the category that defines it
+	Name       string   `json:"name"`        // the key of the value and the filter, e.g. roast
+	Title      string   `json:"title"`       // e.g. Roast level
+	Type       string   `json:"type"`        // text | number | boolean | select
+	Values     []string `json:"values"`      // allowed values of a select
+	Unit       string   `json:"unit,omitempty"`
+	IsRequired bool     `json:"is_required"`
+	Position   int      `json:"position"` // display order, lower comes first
+}
+
+type AttributeReq struct {
+	Id         string   `json:"-"`
+	CategoryId int      `json:"-"`
+	Name       string   `json:"name"` // lowercase letters, digits and _, can't be changed
+	Title      string   `json:"title"`
+	Type       string   `json:"type"` // can't be changed
+	Values     []string `json:"values"`
+	Unit       *string  `json:"unit"`
+	IsRequired *bool    `json:"is_required"`
+	Position   *int     `json:"position"`
 }
 
 type GenerateApiKeyRes struct {
 	ApiKey string `json:"api_key"`
 }
+
+type CategoryUpdateReq struct {
+	Id       int             `json:"-"`
+	Title    string          `json:"title"`
+	Slug     string          `json:"slug"`
+	Position *int            `json:"position"`
+	ParentId *int            `json:"parent_id"` // 0 moves the category to the root
+	Image    *entities.Image `json:"image"`
+}
+
+type CategoryRemoveReq struct {
+	CategoryId int `json:"-"`
+	ReassignTo int `query:"reassign_to"` // category that takes over the products
+}
+
 type CategoryRemoveRes struct {
-	CategoryId int `json:"category_id"`
+	CategoryId    int `json:"category_id"`
+	ReassignTo    int `json:"reassign_to,omitempty"`
+	ProductsMoved int `json:"products_moved"`
 }
\ No newline at end of file
@@ -1,6 +1,8 @@
 package appinfoHandlers
 
 import (
+	"net/url"
+	"path"
 	"strconv"
 	"strings"
 
@@ -15,17 +17,31 @@ import (
 type appinfoHandlersErrCode string
 
 const (
-	generateApiKeyErr appinfoHandlersErrCode = "appinfo-001"
-	findCategoryErr   appinfoHandlersErrCode = "appinfo-002"
-	addCategoryErr    appinfoHandlersErrCode = "appinfo-003"
-	removeCategoryErr appinfoHandlersErrCode = "appinfo-004"
+	generateApiKeyErr   appinfoHandlersErrCode = "appinfo-001"
+	findCategoryErr     appinfoHandlersErrCode = "appinfo-002"
+	addCategoryErr      appinfoHandlersErrCode = "appinfo-003"
+	removeCategoryErr   appinfoHandlersErrCode = "appinfo-004"
+	findCategoryTreeErr appinfoHandlersErrCode = "appinfo-005"
+	updateCategoryErr   appinfoHandlersErrCode = "appinfo-006"
+	findCategorySlugErr appinfoHandlersErrCode = "appinfo-007"
+	findAttributeErr    appinfoHandlersErrCode = "appinfo-008"
+	addAttributeErr     appinfoHandlersErrCode = "appinfo-009"
+	updateAttributeErr  appinfoHandlersErrCode = "appinfo-010"
+	removeAttributeErr  appinfoHandlersErrCode = "appinfo-011"
 )
 
 type IAppinfoHandler interface {
 	GenerateApiKey(c fiber.Ctx) error
 	FindCategory(c fiber.Ctx) error
+	FindCategoryTree(c fiber.Ctx) error
+	FindCategoryBySlug(c fiber.Ctx) error
 	AddCategory(c fiber.Ctx) error
+	UpdateCategory(c fiber.Ctx) error
 	RemoveCategory(c fiber.Ctx) error
+	FindAttribute(c fiber.Ctx) error
+	AddAttribute(c fiber.Ctx) error
+	UpdateAttribute(c fiber.Ctx) error
+	RemoveAttribute(c fiber.Ctx) error
 }
 
 type appinfoHandler struct {
@@ -77,11 +93,14 @@ func (h *appinfoHandler) GenerateApiKey(c fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Param title path string true "Title"
+// @Param sort query string false "Sort fields, e.g. title:asc, fields are id | title | position" default(id:asc)
 // @Security BearerAuth
 // @Success 200 {array} appinfo.Category
 // @Router /appinfo/categories/{title} [get]
 func (h *appinfoHandler) FindCategory(c fiber.Ctx) error {
-	req := new(appinfo.CategoryFilter)
+	req := &appinfo.CategoryFilter{
+		SortReq: &entities.SortReq{},
+	}
 	if err := c.Bind().Query(req); err != nil {
 		return entities.NewResponse(c).Error(
 			fiber.StatusBadRequest,
@@ -90,6 +109,14 @@ func (h *appinfoHandler) FindCategory(c fiber.Ctx) error {
 		).Res()
 	}
 
+	if err := req.SortReq.Parse(appinfo.SortFields, "id:asc"); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findCategoryErr),
+			err.Error(),
+		).Res()
+	}
+
 	category, err := h.appinfoUsecases.FindCategory(req)
 	if err != nil {
 		return entities.NewResponse(c).Error(
@@ -105,6 +132,65 @@ func (h *appinfoHandler) FindCategory(c fiber.Ctx) error {
 	).Res()
 }
 
+// @Summary Find Category By Slug
+// @Description Find a Category by its slug, an old slug responds 301 with the current slug in Location
+// @Tags Categories
+// @Accept  json
+// @Produce  json
+// @Param slug path string true "Slug"
+// @Success 200 {object} appinfo.Category
+// @Success 301 {object} appinfo.SlugRes
+// @Router /appinfo/categories/by-slug/{slug} [get]
+func (h *appinfoHandler) FindCategoryBySlug(c fiber.Ctx) error {
+	res, err := h.appinfoUsecases.FindCategorySlug(strings.Trim(c.Params("slug"), " "))
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findCategorySlugErr),
+			err.Error(),
+		).Res()
+	}
+
+	if res.Moved {
+		c.Set(fiber.HeaderLocation, path.Dir(c.Path())+"/"+url.PathEscape(res.Slug))
+		return entities.NewResponse(c).Success(fiber.StatusMovedPermanently, res).Res()
+	}
+
+	category, err := h.appinfoUsecases.FindOneCategory(res.Id)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findCategorySlugErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, category).Res()
+}
+
+// @Summary Find Category Tree
+// @Description Find Categories nested under their parents
+// @Tags Categories
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} appinfo.Category
+// @Router /appinfo/categories/tree [get]
+func (h *appinfoHandler) FindCategoryTree(c fiber.Ctx) error {
+	tree, err := h.appinfoUsecases.FindCategoryTree()
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.ErrInternalServerError.Code,
+			string(findCategoryTreeErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(
+		fiber.StatusOK,
+		tree,
+	).Res()
+}
+
 // @Summary Add Caregory
 // @Description Add Caregory
 // @Tags Categories
@@ -146,13 +232,67 @@ func (h *appinfoHandler) AddCategory(c fiber.Ctx) error {
 	).Res()
 }
 
-// @Summary Delete File
-// @Description Delete File
-// @Tags Files
+// @Summary Update Category
+// @Description Rename, reorder or move a Category, or change its slug and image
+// @Tags Categories
 // @Accept  json
 // @Produce  json
 // @Security BearerAuth
 // @Param category_id path string true "Category Id"
+// @Param request body appinfo.CategoryUpdateReq true "Category Update Request"
+// @Success 200 {object} appinfo.Category
+// @Router /appinfo/categories/{category_id} [patch]
+func (h *appinfoHandler) UpdateCategory(c fiber.Ctx) error {
+	categoryId, err := strconv.Atoi(strings.Trim(c.Params("category_id"), " "))
+	if err != nil || categoryId <= 0 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateCategoryErr),
+			"invalid category_id",
+		).Res()
+	}
+
+	req := new(appinfo.CategoryUpdateReq)
+	if err := c.Bind().Body(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateCategoryErr),
+			err.Error(),
+		).Res()
+	}
+	req.Id = categoryId
+
+	if req.Title == "" && req.Slug == "" && req.Position == nil && req.ParentId == nil && req.Image == nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateCategoryErr),
+			"request body is empty",
+		).Res()
+	}
+
+	category, err := h.appinfoUsecases.UpdateCategory(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateCategoryErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(
+		fiber.StatusOK,
+		category,
+	).Res()
+}
+
+// @Summary Delete Category
+// @Description Delete a Category, its products are moved to reassign_to and its children to its parent
+// @Tags Categories
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param category_id path string true "Category Id"
+// @Param reassign_to query int false "Category that takes over the products, required when the category still has products"
 // @Success 200 {object} appinfo.CategoryRemoveRes
 // @Router /appinfo/categories/{category_id} [delete]
 func (h *appinfoHandler) RemoveCategory(c fiber.Ctx) error {
@@ -174,9 +314,21 @@ func (h *appinfoHandler) RemoveCategory(c fiber.Ctx) error {
 		).Res()
 	}
 
-	if err := h.appinfoUsecases.DeleteCategory(categoryIdInt); err != nil {
+	req := &appinfo.CategoryRemoveReq{
+		CategoryId: categoryIdInt,
+	}
+	if err := c.Bind().Query(req); err != nil {
 		return entities.NewResponse(c).Error(
-			fiber.ErrInternalServerError.Code,
+			fiber.StatusBadRequest,
+			string(removeCategoryErr),
+			err.Error(),
+		).Res()
+	}
+
+	res, err := h.appinfoUsecases.DeleteCategory(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
 			string(removeCategoryErr),
 			err.Error(),
 		).Res()
@@ -184,10 +336,173 @@ func (h *appinfoHandler) RemoveCategory(c fiber.Ctx) error {
 
 	return entities.NewResponse(c).Success(
 		fiber.StatusOK,
-		&struct {
-			CategoryId int `json:"category_id"`
-		}{
-			CategoryId: categoryIdInt,
-		},
+		res,
+	).Res()
+}
+
+// @Summary Find Category Attributes
+// @Description Attributes of the products of a Category, with the ones it inherits from its parents
+// @Tags Categories
+// @Accept  json
+// @Produce  json
+// @Param category_id path string true "Category Id"
+// @Success 200 {array} appinfo.Attribute
+// @Router /appinfo/categories/{category_id}/attributes [get]
+func (h *appinfoHandler) FindAttribute(c fiber.Ctx) error {
+	categoryId, err := strconv.Atoi(strings.Trim(c.Params("category_id"), " "))
+	if err != nil || categoryId <= 0 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findAttributeErr),
+			"invalid category_id",
+		).Res()
+	}
+
+	attributes, err := h.appinfoUsecases.FindAttribute(categoryId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findAttributeErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(
+		fiber.StatusOK,
+		attributes,
+	).Res()
+}
+
+// @Summary Add Category Attribute
+// @Description Define an attribute for the products of a Category and its children
+// @Tags Categories
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param category_id path string true "Category Id"
+// @Param request body appinfo.AttributeReq true "Attribute Request"
+// @Success 201 {object} appinfo.Attribute
+// @Router /appinfo/categories/{category_id}/attributes [post]
+func (h *appinfoHandler) AddAttribute(c fiber.Ctx) error {
+	categoryId, err := strconv.Atoi(strings.Trim(c.Params("category_id"), " "))
+	if err != nil || categoryId <= 0 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(addAttributeErr),
+			"invalid category_id",
+		).Res()
+	}
+
+	req := new(appinfo.AttributeReq)
+	if err := c.Bind().Body(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(addAttributeErr),
+			err.Error(),
+		).Res()
+	}
+	req.CategoryId = categoryId
+
+	attribute, err := h.appinfoUsecases.InsertAttribute(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(addAttributeErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(
+		fiber.StatusCreated,
+		attribute,
+	).Res()
+}
+
+// @Summary Update Category Attribute
+// @Description Change the title, values, unit, order or requirement of an attribute, its name and type are fixed
+// @Tags Categories
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param category_id path string true "Category Id"
+// @Param attribute_id path string true "Attribute Id"
+// @Param request body appinfo.AttributeReq true "Attribute Request"
+// @Success 200 {object} appinfo.Attribute
+// @Router /appinfo/categories/{category_id}/attributes/{attribute_id} [patch]
+func (h *appinfoHandler) UpdateAttribute(c fiber.Ctx) error {
+	categoryId, err := strconv.Atoi(strings.Trim(c.Params("category_id"), " "))
+	if err != nil || categoryId <= 0 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateAttributeErr),
+			"invalid category_id",
+		).Res()
+	}
+
+	req := new(appinfo.AttributeReq)
+	if err := c.Bind().Body(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateAttributeErr),
+			err.Error(),
+		).Res()
+	}
+	req.Id = strings.Trim(c.Params("attribute_id"), " ")
+	req.CategoryId = categoryId
+
+	if req.Title == "" && req.Values == nil && req.Unit == nil && req.IsRequired == nil && req.Position == nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateAttributeErr),
+			"request body is empty",
+		).Res()
+	}
+
+	attribute, err := h.appinfoUsecases.UpdateAttribute(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateAttributeErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(
+		fiber.StatusOK,
+		attribute,
+	).Res()
+}
+
+// @Summary Delete Category Attribute
+// @Description Delete an attribute and its values from the products of the Category
+// @Tags Categories
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param category_id path string true "Category Id"
+// @Param attribute_id path string true "Attribute Id"
+// @Success 200
+// @Router /appinfo/categories/{category_id}/attributes/{attribute_id} [delete]
+func (h *appinfoHandler) RemoveAttribute(c fiber.Ctx) error {
+	categoryId, err := strconv.Atoi(strings.Trim(c.Params("category_id"), " "))
+	if err != nil || categoryId <= 0 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(removeAttributeErr),
+			"invalid category_id",
+		).Res()
+	}
+
+	if err := h.appinfoUsecases.DeleteAttribute(categoryId, strings.Trim(c.Params("attribute_id"), " ")); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(removeAttributeErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(
+		fiber.StatusOK,
+		nil,
 	).Res()
 }
@@ -2,6 +2,9 @@ package appinfoRepositories
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -11,8 +14,17 @@ import (
 
 type IAppinfoRepository interface {
 	FindCategory(req *appinfo.CategoryFilter) ([]*appinfo.Category, error)
+	FindOneCategory(categoryId int) (*appinfo.Category, error)
+	FindCategorySlug(slug string) (*appinfo.SlugRes, error)
+	FindSlugs(slug string, categoryId int) ([]string, error)
+	FindAttribute(categoryId int) ([]*appinfo.Attribute, error)
+	FindOneAttribute(attributeId string) (*appinfo.Attribute, error)
+	InsertAttribute(req *appinfo.AttributeReq) (string, error)
+	UpdateAttribute(req *appinfo.AttributeReq) error
+	DeleteAttribute(attributeId string) error
 	InsertCategory(req []*appinfo.Category) error
-	DeleteCategory(categoryId int) error
+	UpdateCategory(req *appinfo.CategoryUpdateReq) error
+	DeleteCategory(req *appinfo.CategoryRemoveReq) (int, error)
 }
 
 type appinfoRepository struct {
@@ -25,11 +37,17 @@ func AppinfoRepository(db *sqlx.DB) IAppinfoRepository {
 	}
 }
 
+const categoryColumns = `
+		"id",
+		"title",
+		"slug",
+		"position",
+		"image",
+		"parent_id"`
+
 func (r *appinfoRepository) FindCategory(req *appinfo.CategoryFilter) ([]*appinfo.Category, error) {
 	query := `
-	SELECT
-		"id",
-		"title"
+	SELECT` + categoryColumns + `
 	FROM "categories"`
 
 	filterValues := make([]any, 0)
@@ -37,20 +55,111 @@ func (r *appinfoRepository) FindCategory(req *appinfo.CategoryFilter) ([]*appinf
 		query += " WHERE (LOWER(title) LIKE LOWER($1))"
 		filterValues = append(filterValues, "%"+strings.ToLower(req.Title)+"%")
 	}
+	query += req.SortReq.Query(appinfo.SortFields, `"id" ASC`)
 
-	categories := make([]*appinfo.Category, 0)
-	if err := r.db.Select(&categories, query, filterValues...); err != nil {
+	query = `
+	SELECT
+		COALESCE(array_to_json(array_agg("t")), '[]'::json)
+	FROM (` + query + `) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, filterValues...); err != nil {
 		return nil, fmt.Errorf("categories are not found")
 	}
 
+	categories := make([]*appinfo.Category, 0)
+	if err := json.Unmarshal(raw, &categories); err != nil {
+		return nil, fmt.Errorf("unmarshal categories failed: %w", err)
+	}
+
 	return categories, nil
 }
 
+func (r *appinfoRepository) FindOneCategory(categoryId int) (*appinfo.Category, error) {
+	query := `
+	SELECT
+		to_json("t")
+	FROM (
+		SELECT` + categoryColumns + `
+		FROM "categories"
+		WHERE "id" = $1
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, categoryId); err != nil {
+		return nil, fmt.Errorf("category not found")
+	}
+
+	category := new(appinfo.Category)
+	if err := json.Unmarshal(raw, category); err != nil {
+		return nil, fmt.Errorf("unmarshal category failed: %w", err)
+	}
+	return category, nil
+}
+
+// FindCategorySlug finds the category of a current or an old slug, the
+// current slug comes first
+func (r *appinfoRepository) FindCategorySlug(slug string) (*appinfo.SlugRes, error) {
+	query := `
+	SELECT
+		"id",
+		"slug",
+		FALSE AS "moved"
+	FROM "categories"
+	WHERE "slug" = $1
+	UNION ALL
+	SELECT
+		"c"."id",
+		"c"."slug",
+		TRUE AS "moved"
+	FROM "categories_slugs" "cs"
+		INNER JOIN "categories" "c" ON "c"."id" = "cs"."category_id"
+	WHERE "cs"."slug" = $1
+	ORDER BY "moved"
+	LIMIT 1;`
+
+	res := new(appinfo.SlugRes)
+	if err := r.db.Get(res, query, slug); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category slug: %w", err)
+	}
+	return res, nil
+}
+
+// FindSlugs lists the current and old slugs of the other categories that are
+// the slug or the slug with a number suffix
+func (r *appinfoRepository) FindSlugs(slug string, categoryId int) ([]string, error) {
+	query := `
+	SELECT
+		"slug"
+	FROM "categories"
+	WHERE ("slug" = $1 OR "slug" LIKE $1 || '-%')
+	AND "id" != $2
+	UNION
+	SELECT
+		"slug"
+	FROM "categories_slugs"
+	WHERE ("slug" = $1 OR "slug" LIKE $1 || '-%')
+	AND "category_id" != $2;`
+
+	slugs := make([]string, 0)
+	if err := r.db.Select(&slugs, query, slug, categoryId); err != nil {
+		return nil, fmt.Errorf("failed to get slugs: %w", err)
+	}
+	return slugs, nil
+}
+
 func (r *appinfoRepository) InsertCategory(req []*appinfo.Category) error {
 	ctx := context.Background()
 	query := `
 	INSERT INTO categories (
-		"title"
+		"title",
+		"slug",
+		"position",
+		"image",
+		"parent_id"
 	) 
 	VALUES 
 	`
@@ -61,12 +170,13 @@ func (r *appinfoRepository) InsertCategory(req []*appinfo.Category) error {
 
 	valuesStack := make([]any, 0)
 	for i, category := range req {
-		valuesStack = append(valuesStack, category.Title)
+		valuesStack = append(valuesStack, category.Title, category.Slug, category.Position, category.Image, category.ParentId)
 
+		n := i * 5
 		if i != len(req)-1 {
-			query += fmt.Sprintf("($%d),", i+1)
+			query += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d),", n+1, n+2, n+3, n+4, n+5)
 		} else {
-			query += fmt.Sprintf("($%d)", i+1)
+			query += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5)
 		}
 	}
 
@@ -96,13 +206,382 @@ func (r *appinfoRepository) InsertCategory(req []*appinfo.Category) error {
 	return nil
 }
 
-func (r *appinfoRepository) DeleteCategory(categoryId int) error {
+func (r *appinfoRepository) UpdateCategory(req *appinfo.CategoryUpdateReq) error {
 	query := `
-	DELETE FROM "categories"
-	WHERE "id" = $1
+		UPDATE "categories" SET
 	`
-	if _, err := r.db.ExecContext(context.Background(), query, categoryId); err != nil {
-		return fmt.Errorf("delete category failed: %w", err)
+
+	queryWhereStack := make([]string, 0)
+	values := make([]any, 0)
+	lastIndex := 1
+
+	if req.Title != "" {
+		values = append(values, req.Title)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"title" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.Slug != "" {
+		values = append(values, req.Slug)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"slug" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.Position != nil {
+		values = append(values, *req.Position)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"position" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.Image != nil {
+		values = append(values, req.Image)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"image" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.ParentId != nil {
+		values = append(values, *req.ParentId)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"parent_id" = NULLIF($%d::INT, 0)?`, lastIndex))
+		lastIndex++
+	}
+
+	values = append(values, req.Id)
+
+	queryClose := fmt.Sprintf(` WHERE "id" = $%d`, lastIndex)
+
+	for i := range queryWhereStack {
+		if i != len(queryWhereStack)-1 {
+			query += strings.Replace(queryWhereStack[i], "?", ",", 1)
+		} else {
+			query += strings.Replace(queryWhereStack[i], "?", "", 1)
+		}
+	}
+	query += queryClose
+
+	result, err := r.db.ExecContext(context.Background(), query, values...)
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("category not found")
+	}
+	return nil
+}
+
+// DeleteCategory removes a category and moves its products to req.ReassignTo,
+// its children are attached to its parent. It returns how many products were moved.
+func (r *appinfoRepository) DeleteCategory(req *appinfo.CategoryRemoveReq) (int, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var parentId *int
+	if err := tx.GetContext(ctx, &parentId, `SELECT "parent_id" FROM "categories" WHERE "id" = $1 FOR UPDATE;`, req.CategoryId); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("category not found")
+		}
+		return 0, fmt.Errorf("delete category failed: %w", err)
+	}
+
+	var products int
+	if err := tx.GetContext(ctx, &products, `SELECT COUNT(*) FROM "products_categories" WHERE "category_id" = $1;`, req.CategoryId); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("delete category failed: %w", err)
+	}
+
+	if products > 0 {
+		if req.ReassignTo == 0 {
+			tx.Rollback()
+			return 0, fmt.Errorf("category still has %d products, pass reassign_to to move them", products)
+		}
+
+		var exists bool
+		if err := tx.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM "categories" WHERE "id" = $1 FOR SHARE);`, req.ReassignTo); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("delete category failed: %w", err)
+		}
+		if !exists {
+			tx.Rollback()
+			return 0, fmt.Errorf("reassign_to category not found")
+		}
+
+		// Keep created_at, so a moved main category stays the main category
+		query := `
+		INSERT INTO "products_categories" (
+			"product_id",
+			"category_id",
+			"created_at"
+		)
+		SELECT
+			"product_id",
+			$2,
+			"created_at"
+		FROM "products_categories"
+		WHERE "category_id" = $1
+		ON CONFLICT ("product_id", "category_id") DO UPDATE
+		SET "created_at" = LEAST("products_categories"."created_at", EXCLUDED."created_at");`
+
+		if _, err := tx.ExecContext(ctx, query, req.CategoryId, req.ReassignTo); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to move products: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM "products_categories" WHERE "category_id" = $1;`, req.CategoryId); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to move products: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE "categories" SET "parent_id" = $2 WHERE "parent_id" = $1;`, req.CategoryId, parentId); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to move children: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "categories" WHERE "id" = $1;`, req.CategoryId); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("delete category failed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return products, nil
+}
+
+const attributeColumns = `
+		"a"."id",
+		"a"."category_id",
+		"a"."name",
+		"a"."title",
+		"a"."type",
+		"a"."values",
+		"a"."unit",
+		"a"."is_required",
+		"a"."position"`
+
+// FindAttribute lists the attributes of the category with the ones it gets
+// from its ancestors, the closest category wins when two define the same name
+func (r *appinfoRepository) FindAttribute(categoryId int) ([]*appinfo.Attribute, error) {
+	query := `
+	SELECT
+		COALESCE(array_to_json(array_agg("t")), '[]'::json)
+	FROM (
+		SELECT
+			*
+		FROM (
+			SELECT DISTINCT ON ("a"."name")` + attributeColumns + `
+			FROM "categories_closure" "cc"
+				INNER JOIN "categories_attributes" "a" ON "a"."category_id" = "cc"."ancestor_id"
+			WHERE "cc"."descendant_id" = $1
+			ORDER BY "a"."name", "cc"."depth"
+		) AS "d"
+		ORDER BY "position", "name"
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, categoryId); err != nil {
+		return nil, fmt.Errorf("failed to get attributes: %w", err)
+	}
+
+	attributes := make([]*appinfo.Attribute, 0)
+	if err := json.Unmarshal(raw, &attributes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attributes: %w", err)
+	}
+	return attributes, nil
+}
+
+func (r *appinfoRepository) FindOneAttribute(attributeId string) (*appinfo.Attribute, error) {
+	query := `
+	SELECT
+		to_json("t")
+	FROM (
+		SELECT` + attributeColumns + `
+		FROM "categories_attributes" "a"
+		WHERE "a"."id"::TEXT = $1
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, attributeId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("attribute not found")
+		}
+		return nil, fmt.Errorf("failed to get attribute: %w", err)
+	}
+
+	attribute := new(appinfo.Attribute)
+	if err := json.Unmarshal(raw, attribute); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attribute: %w", err)
+	}
+	return attribute, nil
+}
+
+func (r *appinfoRepository) InsertAttribute(req *appinfo.AttributeReq) (string, error) {
+	query := `
+	INSERT INTO "categories_attributes" (
+		"category_id",
+		"name",
+		"title",
+		"type",
+		"values",
+		"unit",
+		"is_required",
+		"position"
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT ("category_id", "name") DO NOTHING
+	RETURNING "id"::TEXT;`
+
+	var unit string
+	if req.Unit != nil {
+		unit = *req.Unit
+	}
+	var isRequired bool
+	if req.IsRequired != nil {
+		isRequired = *req.IsRequired
+	}
+	var position int
+	if req.Position != nil {
+		position = *req.Position
+	}
+
+	var attributeId string
+	if err := r.db.QueryRowxContext(
+		context.Background(),
+		query,
+		req.CategoryId,
+		req.Name,
+		req.Title,
+		req.Type,
+		req.Values,
+		unit,
+		isRequired,
+		position,
+	).Scan(&attributeId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("attribute %s already exists in the category", req.Name)
+		}
+		return "", fmt.Errorf("failed to insert attribute: %w", err)
+	}
+	return attributeId, nil
+}
+
+func (r *appinfoRepository) UpdateAttribute(req *appinfo.AttributeReq) error {
+	query := `
+		UPDATE "categories_attributes" SET
+	`
+
+	queryWhereStack := make([]string, 0)
+	values := make([]any, 0)
+	lastIndex := 1
+
+	if req.Title != "" {
+		values = append(values, req.Title)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"title" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.Values != nil {
+		values = append(values, req.Values)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"values" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.Unit != nil {
+		values = append(values, *req.Unit)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"unit" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.IsRequired != nil {
+		values = append(values, *req.IsRequired)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"is_required" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.Position != nil {
+		values = append(values, *req.Position)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"position" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	values = append(values, req.Id)
+
+	queryClose := fmt.Sprintf(` WHERE "id"::TEXT = $%d`, lastIndex)
+
+	for i := range queryWhereStack {
+		if i != len(queryWhereStack)-1 {
+			query += strings.Replace(queryWhereStack[i], "?", ",", 1)
+		} else {
+			query += strings.Replace(queryWhereStack[i], "?", "", 1)
+		}
+	}
+	query += queryClose
+
+	result, err := r.db.ExecContext(context.Background(), query, values...)
+	if err != nil {
+		return fmt.Errorf("failed to update attribute: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("attribute not found")
+	}
+	return nil
+}
+
+// DeleteAttribute removes the attribute and its values from the products of
+// the category and its descendants, unless another category still defines it
+func (r *appinfoRepository) DeleteAttribute(attributeId string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	attribute := struct {
+		CategoryId int    `db:"category_id"`
+		Name       string `db:"name"`
+	}{}
+	if err := tx.GetContext(ctx, &attribute, `DELETE FROM "categories_attributes" WHERE "id"::TEXT = $1 RETURNING "category_id", "name";`, attributeId); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("attribute not found")
+		}
+		return fmt.Errorf("failed to delete attribute: %w", err)
+	}
+
+	query := `
+	UPDATE "products" "p" SET
+		"attributes" = "p"."attributes" - $2
+	WHERE "p"."attributes" ? $2
+	AND EXISTS (
+		SELECT 1
+		FROM "products_categories" "pc"
+			INNER JOIN "categories_closure" "cc" ON "cc"."descendant_id" = "pc"."category_id"
+		WHERE "pc"."product_id" = "p"."id"
+		AND "cc"."ancestor_id" = $1
+	)
+	AND NOT EXISTS (
+		SELECT 1
+		FROM "products_categories" "pc"
+			INNER JOIN "categories_closure" "cc" ON "cc"."descendant_id" = "pc"."category_id"
+			INNER JOIN "categories_attributes" "a" ON "a"."category_id" = "cc"."ancestor_id"
+		WHERE "pc"."product_id" = "p"."id"
+		AND "a"."name" = $2
+	);`
+
+	if _, err := tx.ExecContext(ctx, query, attribute.CategoryId, attribute.Name); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete attribute: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
 	}
 	return nil
 }
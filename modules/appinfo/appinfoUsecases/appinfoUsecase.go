@@ -1,14 +1,29 @@
 package appinfoUsecases
 
 import (
+	"fmt"
+	"log"
+	"slices"
+
 	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo/appinfoRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/pkg/utils"
 )
 
 type IAppinfoUsecase interface {
 	FindCategory(req *appinfo.CategoryFilter) ([]*appinfo.Category, error)
+	FindCategoryTree() ([]*appinfo.Category, error)
+	FindOneCategory(categoryId int) (*appinfo.Category, error)
+	FindCategorySlug(slug string) (*appinfo.SlugRes, error)
 	InsertCategory(req []*appinfo.Category) error
-	DeleteCategory(categoryId int) error
+	UpdateCategory(req *appinfo.CategoryUpdateReq) (*appinfo.Category, error)
+	DeleteCategory(req *appinfo.CategoryRemoveReq) (*appinfo.CategoryRemoveRes, error)
+	ResetCategorySlug() error
+	FindAttribute(categoryId int) ([]*appinfo.Attribute, error)
+	InsertAttribute(req *appinfo.AttributeReq) (*appinfo.Attribute, error)
+	UpdateAttribute(req *appinfo.AttributeReq) (*appinfo.Attribute, error)
+	DeleteAttribute(categoryId int, attributeId string) error
 }
 
 type appinfoUsecase struct {
@@ -29,7 +44,72 @@ func (u *appinfoUsecase) FindCategory(req *appinfo.CategoryFilter) ([]*appinfo.C
 	return category, nil
 }
 
+// FindCategoryTree nests every category under its parent.
+func (u *appinfoUsecase) FindCategoryTree() ([]*appinfo.Category, error) {
+	categories, err := u.appinfoRepository.FindCategory(&appinfo.CategoryFilter{
+		SortReq: &entities.SortReq{
+			Fields: []*entities.SortField{{Field: "position"}, {Field: "title"}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[int]*appinfo.Category)
+	for _, c := range categories {
+		nodes[c.Id] = c
+	}
+
+	tree := make([]*appinfo.Category, 0)
+	for _, c := range categories {
+		if c.ParentId != nil && nodes[*c.ParentId] != nil {
+			parent := nodes[*c.ParentId]
+			parent.Children = append(parent.Children, c)
+			continue
+		}
+		tree = append(tree, c)
+	}
+	return tree, nil
+}
+
+func (u *appinfoUsecase) FindOneCategory(categoryId int) (*appinfo.Category, error) {
+	return u.appinfoRepository.FindOneCategory(categoryId)
+}
+
+func (u *appinfoUsecase) FindCategorySlug(slug string) (*appinfo.SlugRes, error) {
+	return u.appinfoRepository.FindCategorySlug(slug)
+}
+
+// InsertCategory generates the slugs that are not sent from the titles, with
+// a number suffix when they are taken. A slug that is sent must be free.
 func (u *appinfoUsecase) InsertCategory(req []*appinfo.Category) error {
+	inserted := make([]string, 0)
+	for _, category := range req {
+		override := category.Slug != ""
+		if !override {
+			category.Slug = category.Title
+		}
+		category.Slug = utils.Slugify(category.Slug)
+		if category.Slug == "" {
+			if override {
+				return fmt.Errorf("slug of %q is invalid", category.Title)
+			}
+			category.Slug = "category"
+		}
+
+		taken, err := u.appinfoRepository.FindSlugs(category.Slug, 0)
+		if err != nil {
+			return err
+		}
+		taken = append(taken, inserted...)
+		if override && slices.Contains(taken, category.Slug) {
+			return fmt.Errorf("slug %s is already taken", category.Slug)
+		}
+
+		category.Slug = utils.UniqueSlug(category.Slug, taken)
+		inserted = append(inserted, category.Slug)
+	}
+
 	err := u.appinfoRepository.InsertCategory(req)
 	if err != nil {
 		return err
@@ -38,10 +118,192 @@ func (u *appinfoUsecase) InsertCategory(req []*appinfo.Category) error {
 	return nil
 }
 
-func (u *appinfoUsecase) DeleteCategory(categoryId int) error {
-	err := u.appinfoRepository.DeleteCategory(categoryId)
+func (u *appinfoUsecase) UpdateCategory(req *appinfo.CategoryUpdateReq) (*appinfo.Category, error) {
+	if req.Slug != "" {
+		req.Slug = utils.Slugify(req.Slug)
+		if req.Slug == "" {
+			return nil, fmt.Errorf("slug is invalid")
+		}
+
+		taken, err := u.appinfoRepository.FindSlugs(req.Slug, req.Id)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(taken, req.Slug) {
+			return nil, fmt.Errorf("slug %s is already taken", req.Slug)
+		}
+	}
+	if req.ParentId != nil && *req.ParentId == req.Id {
+		return nil, fmt.Errorf("category can not be its own parent")
+	}
+
+	if err := u.appinfoRepository.UpdateCategory(req); err != nil {
+		return nil, err
+	}
+
+	category, err := u.appinfoRepository.FindOneCategory(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (u *appinfoUsecase) DeleteCategory(req *appinfo.CategoryRemoveReq) (*appinfo.CategoryRemoveRes, error) {
+	if req.ReassignTo == req.CategoryId {
+		return nil, fmt.Errorf("reassign_to must be another category")
+	}
+
+	moved, err := u.appinfoRepository.DeleteCategory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &appinfo.CategoryRemoveRes{
+		CategoryId:    req.CategoryId,
+		ProductsMoved: moved,
+	}
+	if moved > 0 {
+		res.ReassignTo = req.ReassignTo
+	}
+	return res, nil
+}
+
+// ResetCategorySlug makes the slugs that aren't slugs of Slugify again from
+// the titles, the categories from before slugs were transliterated still have
+// Thai slugs. The old slugs stay in the history, so their links redirect.
+func (u *appinfoUsecase) ResetCategorySlug() error {
+	categories, err := u.appinfoRepository.FindCategory(&appinfo.CategoryFilter{
+		SortReq: &entities.SortReq{},
+	})
 	if err != nil {
 		return err
 	}
+
+	var count int
+	for _, category := range categories {
+		if utils.Slugify(category.Slug) == category.Slug {
+			continue
+		}
+
+		slug := utils.Slugify(category.Title)
+		if slug == "" {
+			slug = "category"
+		}
+		taken, err := u.appinfoRepository.FindSlugs(slug, category.Id)
+		if err != nil {
+			return err
+		}
+
+		if err := u.appinfoRepository.UpdateCategory(&appinfo.CategoryUpdateReq{
+			Id:   category.Id,
+			Slug: utils.UniqueSlug(slug, taken),
+		}); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if count > 0 {
+		log.Printf("%d category slugs reset", count)
+	}
+	return nil
+}
+
+func (u *appinfoUsecase) FindAttribute(categoryId int) ([]*appinfo.Attribute, error) {
+	if _, err := u.appinfoRepository.FindOneCategory(categoryId); err != nil {
+		return nil, err
+	}
+	return u.appinfoRepository.FindAttribute(categoryId)
+}
+
+func (u *appinfoUsecase) InsertAttribute(req *appinfo.AttributeReq) (*appinfo.Attribute, error) {
+	if !appinfo.AttributeNameRegexp.MatchString(req.Name) {
+		return nil, fmt.Errorf("name must start with a lowercase letter and contain only lowercase letters, digits and _")
+	}
+	if req.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if !appinfo.AttributeTypes[req.Type] {
+		return nil, fmt.Errorf("type must be text, number, boolean or select")
+	}
+	if req.Values == nil {
+		req.Values = make([]string, 0)
+	}
+	if err := validateAttributeValues(req.Type, req.Values); err != nil {
+		return nil, err
+	}
+	if _, err := u.appinfoRepository.FindOneCategory(req.CategoryId); err != nil {
+		return nil, err
+	}
+
+	attributeId, err := u.appinfoRepository.InsertAttribute(req)
+	if err != nil {
+		return nil, err
+	}
+	return u.appinfoRepository.FindOneAttribute(attributeId)
+}
+
+func (u *appinfoUsecase) UpdateAttribute(req *appinfo.AttributeReq) (*appinfo.Attribute, error) {
+	attribute, err := u.findCategoryAttribute(req.CategoryId, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != "" && req.Name != attribute.Name {
+		return nil, fmt.Errorf("name can not be changed")
+	}
+	if req.Type != "" && req.Type != attribute.Type {
+		return nil, fmt.Errorf("type can not be changed")
+	}
+	if req.Values != nil {
+		if err := validateAttributeValues(attribute.Type, req.Values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.appinfoRepository.UpdateAttribute(req); err != nil {
+		return nil, err
+	}
+	return u.appinfoRepository.FindOneAttribute(req.Id)
+}
+
+func (u *appinfoUsecase) DeleteAttribute(categoryId int, attributeId string) error {
+	if _, err := u.findCategoryAttribute(categoryId, attributeId); err != nil {
+		return err
+	}
+	return u.appinfoRepository.DeleteAttribute(attributeId)
+}
+
+// findCategoryAttribute finds the attribute the category defines itself, the
+// inherited ones are changed through their own category
+func (u *appinfoUsecase) findCategoryAttribute(categoryId int, attributeId string) (*appinfo.Attribute, error) {
+	attribute, err := u.appinfoRepository.FindOneAttribute(attributeId)
+	if err != nil {
+		return nil, err
+	}
+	if attribute.CategoryId != categoryId {
+		return nil, fmt.Errorf("attribute not found")
+	}
+	return attribute, nil
+}
+
+func validateAttributeValues(attributeType string, values []string) error {
+	if attributeType != "select" {
+		if len(values) > 0 {
+			return fmt.Errorf("values are only allowed for a select")
+		}
+		return nil
+	}
+
+	if len(values) == 0 {
+		return fmt.Errorf("a select needs at least one value")
+	}
+	for i, v := range values {
+		if v == "" {
+			return fmt.Errorf("values can not be empty")
+		}
+		if slices.Contains(values[:i], v) {
+			return fmt.Errorf("value %s is duplicated", v)
+		}
+	}
 	return nil
 }
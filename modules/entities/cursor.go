@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CursorReq is the keyset pagination mode, it's used instead of page when
+// cursor, after or before is sent.
+type CursorReq struct {
+	Cursor    bool    `query:"cursor"` // cursor mode for the first page
+	After     string  `query:"after"`
+	Before    string  `query:"before"`
+	WithTotal bool    `query:"with_total"`
+	Key       *Cursor `query:"-" json:"-"`
+}
+
+// Cursor points at a row by its sort value and id, the order is kept inside
+// so a cursor can't be used with another order.
+type Cursor struct {
+	OrderBy string `json:"o"`
+	SortBy  string `json:"s"`
+	Value   string `json:"v"`
+	Id      string `json:"i"`
+}
+
+type CursorRes struct {
+	Data       any    `json:"data"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+	TotalItem  *int   `json:"total_item,omitempty"`
+}
+
+func (r *CursorReq) IsCursor() bool {
+	return r != nil && (r.Cursor || r.After != "" || r.Before != "")
+}
+
+func (r *CursorReq) IsBefore() bool {
+	return r != nil && r.Before != ""
+}
+
+// Decode reads the after or before cursor into Key.
+func (r *CursorReq) Decode(orderBy, sortBy string) error {
+	if r.After != "" && r.Before != "" {
+		return fmt.Errorf("use either after or before")
+	}
+
+	raw := r.After
+	if r.Before != "" {
+		raw = r.Before
+	}
+	if raw == "" {
+		return nil
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return fmt.Errorf("invalid cursor")
+	}
+
+	key := new(Cursor)
+	if err := json.Unmarshal(bytes, key); err != nil {
+		return fmt.Errorf("invalid cursor")
+	}
+
+	if !strings.EqualFold(key.OrderBy, orderBy) || !strings.EqualFold(key.SortBy, sortBy) {
+		return fmt.Errorf("cursor does not match order_by and sort_by")
+	}
+
+	r.Key = key
+	return nil
+}
+
+func (c *Cursor) Encode() string {
+	bytes, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+// NewCursorRes takes limit + 1 rows, the extra row only tells that there is
+// another page. Rows of a before page come in reverse order.
+func NewCursorRes[T any](req *CursorReq, orderBy, sortBy string, limit int, data []T, key func(T) (value, id string)) *CursorRes {
+	hasMore := len(data) > limit
+	if hasMore {
+		data = data[:limit]
+	}
+	if req.IsBefore() {
+		slices.Reverse(data)
+	}
+
+	res := &CursorRes{
+		Data:  data,
+		Limit: limit,
+	}
+	if len(data) == 0 {
+		return res
+	}
+
+	cursor := func(row T) string {
+		value, id := key(row)
+		return (&Cursor{
+			OrderBy: orderBy,
+			SortBy:  sortBy,
+			Value:   value,
+			Id:      id,
+		}).Encode()
+	}
+
+	if req.IsBefore() {
+		if hasMore {
+			res.PrevCursor = cursor(data[0])
+		}
+		res.NextCursor = cursor(data[len(data)-1])
+	} else {
+		if hasMore {
+			res.NextCursor = cursor(data[len(data)-1])
+		}
+		if req.After != "" {
+			res.PrevCursor = cursor(data[0])
+		}
+	}
+	return res
+}
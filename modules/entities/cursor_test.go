@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := &Cursor{
+		OrderBy: "price",
+		SortBy:  "desc",
+		Value:   "150.5",
+		Id:      "P000001",
+	}
+
+	tests := []*CursorReq{
+		{After: cursor.Encode()},
+		{Before: cursor.Encode()},
+	}
+
+	for _, req := range tests {
+		if err := req.Decode("PRICE", "DESC"); err != nil {
+			t.Errorf("Decode(%+v) error = %v", req, err)
+			continue
+		}
+		if req.Key == nil || *req.Key != *cursor {
+			t.Errorf("Decode(%+v) key = %+v, want %+v", req, req.Key, cursor)
+		}
+	}
+}
+
+func TestCursorDecodeInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	other := (&Cursor{OrderBy: "title", SortBy: "desc", Value: "Coffee", Id: "P000001"}).Encode()
+
+	tests := []struct {
+		req     *CursorReq
+		wantErr string
+	}{
+		{&CursorReq{After: "not base64!"}, "invalid cursor"},
+		{&CursorReq{After: base64.StdEncoding.EncodeToString([]byte(`{"o":"title"}`))}, "invalid cursor"},
+		{&CursorReq{Before: encode("not json")}, "invalid cursor"},
+		{&CursorReq{After: encode(`{"o":["title"]}`)}, "invalid cursor"},
+		{&CursorReq{After: other}, "cursor does not match order_by and sort_by"},
+		{&CursorReq{After: encode(`{"o":"price","s":"asc","v":"Coffee","i":"P000001"}`)}, "cursor does not match order_by and sort_by"},
+		{&CursorReq{After: other, Before: other}, "use either after or before"},
+	}
+
+	for _, tt := range tests {
+		err := tt.req.Decode("title", "asc")
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("Decode(%+v) error = %v, want %q", tt.req, err, tt.wantErr)
+		}
+		if tt.req.Key != nil {
+			t.Errorf("Decode(%+v) key = %+v, want nil", tt.req, tt.req.Key)
+		}
+	}
+}
@@ -10,4 +10,6 @@ type PaginationReq struct {
 type SortReq struct {
 	OrderBy string `query:"order_by"`
 	SortBy string `query:"sort_by"`
+	Sort string `query:"sort"` // e.g. price:asc,created_at:desc
+	Fields []*SortField `query:"-" json:"-"`
 }
\ No newline at end of file
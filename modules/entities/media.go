@@ -4,4 +4,5 @@ type Image struct {
 	Id       string `db:"id" json:"id"`
 	Filename string `db:"filename" json:"filename"`
 	Url      string `db:"url" json:"url"`
+	Alt      string `db:"alt" json:"alt,omitempty"` // product images only
 }
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Parse reads sort into Fields, every field has to be a key of columns.
+// order_by and sort_by are still read when sort is not sent.
+func (r *SortReq) Parse(columns map[string]string, defaultSort string) error {
+	sort := r.Sort
+	if sort == "" && r.OrderBy != "" {
+		sort = r.OrderBy + ":" + r.SortBy
+	}
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	r.Fields = make([]*SortField, 0)
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(sort, ",") {
+		field, direction, _ := strings.Cut(strings.TrimSpace(part), ":")
+		field = strings.ToLower(strings.TrimSpace(field))
+
+		if _, ok := columns[field]; !ok {
+			return fmt.Errorf("sort by %s is not supported", field)
+		}
+		if seen[field] {
+			return fmt.Errorf("sort by %s is duplicated", field)
+		}
+		seen[field] = true
+
+		switch strings.ToLower(strings.TrimSpace(direction)) {
+		case "", "asc":
+			r.Fields = append(r.Fields, &SortField{Field: field})
+		case "desc":
+			r.Fields = append(r.Fields, &SortField{Field: field, Desc: true})
+		default:
+			return fmt.Errorf("sort direction of %s must be asc or desc", field)
+		}
+	}
+	return nil
+}
+
+// Query builds the ORDER BY clause, only the columns are written into the SQL
+// and never the request. tieBreaker keeps the order stable between pages.
+func (r *SortReq) Query(columns map[string]string, tieBreaker string) string {
+	orderBy := make([]string, 0, len(r.Fields)+1)
+	for _, f := range r.Fields {
+		column := columns[f.Field]
+		if column == "" {
+			continue
+		}
+		if f.Desc {
+			orderBy = append(orderBy, column+" DESC")
+		} else {
+			orderBy = append(orderBy, column+" ASC")
+		}
+	}
+	if tieBreaker != "" {
+		orderBy = append(orderBy, tieBreaker)
+	}
+	if len(orderBy) == 0 {
+		return ""
+	}
+	return `
+		ORDER BY ` + strings.Join(orderBy, ", ")
+}
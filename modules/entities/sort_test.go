@@ -0,0 +1,75 @@
+package entities
+
+import "testing"
+
+var testSortColumns = map[string]string{
+	"id":         `"p"."id"`,
+	"price":      `"p"."price"`,
+	"created_at": `"p"."created_at"`,
+}
+
+func TestSortReqParse(t *testing.T) {
+	tests := []struct {
+		req     SortReq
+		want    []SortField
+		wantErr string
+	}{
+		{SortReq{}, []SortField{{Field: "id"}}, ""},
+		{SortReq{Sort: "price:desc"}, []SortField{{Field: "price", Desc: true}}, ""},
+		{SortReq{Sort: " Price : DESC , created_at "}, []SortField{{Field: "price", Desc: true}, {Field: "created_at"}}, ""},
+		{SortReq{OrderBy: "price", SortBy: "desc"}, []SortField{{Field: "price", Desc: true}}, ""},
+		{SortReq{Sort: "created_at", OrderBy: "price"}, []SortField{{Field: "created_at"}}, ""},
+		{SortReq{Sort: "stock:asc"}, nil, "sort by stock is not supported"},
+		{SortReq{Sort: "price:up"}, nil, "sort direction of price must be asc or desc"},
+		{SortReq{Sort: "price:asc,price:desc"}, nil, "sort by price is duplicated"},
+		{SortReq{Sort: "price:asc,"}, nil, "sort by  is not supported"},
+	}
+
+	for _, tt := range tests {
+		err := tt.req.Parse(testSortColumns, "id:asc")
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Parse(%+v) error = %v, want %q", tt.req, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%+v) error = %v", tt.req, err)
+			continue
+		}
+
+		if len(tt.req.Fields) != len(tt.want) {
+			t.Errorf("Parse(%+v) fields = %d, want %d", tt.req, len(tt.req.Fields), len(tt.want))
+			continue
+		}
+		for i, f := range tt.req.Fields {
+			if *f != tt.want[i] {
+				t.Errorf("Parse(%+v) field %d = %+v, want %+v", tt.req, i, *f, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestSortReqQuery(t *testing.T) {
+	tests := []struct {
+		fields     []*SortField
+		tieBreaker string
+		want       string
+	}{
+		{nil, "", ""},
+		{nil, `"p"."id" DESC`, `
+		ORDER BY "p"."id" DESC`},
+		{[]*SortField{{Field: "price", Desc: true}}, "", `
+		ORDER BY "p"."price" DESC`},
+		{[]*SortField{{Field: "price"}, {Field: "created_at", Desc: true}}, `"p"."id" ASC`, `
+		ORDER BY "p"."price" ASC, "p"."created_at" DESC, "p"."id" ASC`},
+		{[]*SortField{{Field: "price; DROP TABLE products"}}, "", ""},
+	}
+
+	for _, tt := range tests {
+		req := &SortReq{Fields: tt.fields}
+		if got := req.Query(testSortColumns, tt.tieBreaker); got != tt.want {
+			t.Errorf("Query(%v, %q) = %q, want %q", tt.fields, tt.tieBreaker, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ type FileReq struct {
 	Destination string                `form:"destination"`
 	Extension   string
 	FileName    string
+	Data        []byte // uploaded when there is no File, e.g. a file from a zip
 }
 
 type FileRes struct {
@@ -57,15 +57,18 @@ func (f *filePub) setBucketPublicIAM(ctx context.Context, client *storage.Client
 // streamFileUpload uploads an object via a stream.
 func (u *filesUsecase) uploadWorkers(ctx context.Context, client *storage.Client, jobs <-chan *files.FileReq, results chan<- *files.FileRes, errs chan<- error) {
 	for job := range jobs {
-		container, err := job.File.Open()
-		if err != nil {
-			errs <- err
-			return
-		}
-		b, err := io.ReadAll(container)
-		if err != nil {
-			errs <- err
-			return
+		b := job.Data
+		if job.File != nil {
+			container, err := job.File.Open()
+			if err != nil {
+				errs <- err
+				return
+			}
+			b, err = io.ReadAll(container)
+			if err != nil {
+				errs <- err
+				return
+			}
 		}
 
 		buf := bytes.NewBuffer(b)
@@ -73,7 +76,7 @@ func (u *filesUsecase) uploadWorkers(ctx context.Context, client *storage.Client
 		// Upload an object with storage.Writer.
 		wc := client.Bucket(u.cfg.App().GCPBucket()).Object(job.Destination).NewWriter(ctx)
 
-		if _, err = io.Copy(wc, buf); err != nil {
+		if _, err := io.Copy(wc, buf); err != nil {
 			errs <- fmt.Errorf("io.Copy: %w", err)
 			return
 		}
@@ -0,0 +1,39 @@
+package flashsales
+
+type FlashSaleFilter struct {
+	Status string `query:"status"` // running | upcoming | ended, every flash sale that has not ended by default
+}
+
+// Statuses are the statuses the filter accepts
+var Statuses = map[string]string{
+	"running":  `"f"."start_at" <= now() AND "f"."end_at" > now()`,
+	"upcoming": `"f"."start_at" > now()`,
+	"ended":    `"f"."end_at" <= now()`,
+}
+
+type FlashSale struct {
+	Id        string              `json:"id"`
+	Title     string              `json:"title"`
+	StartAt   string              `json:"start_at"` // RFC3339
+	EndAt     string              `json:"end_at"`
+	Products  []*FlashSaleProduct `json:"products"`
+	CreatedAt string              `json:"created_at"`
+	UpdatedAt string              `json:"updated_at"`
+}
+
+type FlashSaleProduct struct {
+	ProductId        string  `json:"product_id"`
+	Title            string  `json:"title"` // the product title, read only
+	Price            float64 `json:"price"`
+	Qty              int     `json:"qty"` // the total quantity cap
+	SoldQty          int     `json:"sold_qty"`
+	PerCustomerLimit *int    `json:"per_customer_limit"` // null is unlimited
+}
+
+type FlashSaleReq struct {
+	Id       string              `json:"-"`
+	Title    string              `json:"title"`
+	StartAt  string              `json:"start_at"` // RFC3339
+	EndAt    string              `json:"end_at"`
+	Products []*FlashSaleProduct `json:"products"` // replaces the products when it is given
+}
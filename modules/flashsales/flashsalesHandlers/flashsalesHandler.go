@@ -0,0 +1,181 @@
+package flashsalesHandlers
+
+import (
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/config"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/flashsales"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/flashsales/flashsalesUsecases"
+	"github.com/gofiber/fiber/v3"
+)
+
+type flashsalesHandlersErrCode string
+
+const (
+	findFlashSaleErr    flashsalesHandlersErrCode = "flashsales-001"
+	findOneFlashSaleErr flashsalesHandlersErrCode = "flashsales-002"
+	insertFlashSaleErr  flashsalesHandlersErrCode = "flashsales-003"
+	updateFlashSaleErr  flashsalesHandlersErrCode = "flashsales-004"
+	deleteFlashSaleErr  flashsalesHandlersErrCode = "flashsales-005"
+)
+
+type IFlashsalesHandler interface {
+	FindFlashSale(c fiber.Ctx) error
+	FindOneFlashSale(c fiber.Ctx) error
+	InsertFlashSale(c fiber.Ctx) error
+	UpdateFlashSale(c fiber.Ctx) error
+	DeleteFlashSale(c fiber.Ctx) error
+}
+
+type flashsalesHandler struct {
+	cfg               config.IConfig
+	flashsalesUsecase flashsalesUsecases.IFlashsalesUsecase
+}
+
+func FlashsalesHandler(cfg config.IConfig, flashsalesUsecase flashsalesUsecases.IFlashsalesUsecase) IFlashsalesHandler {
+	return &flashsalesHandler{
+		cfg:               cfg,
+		flashsalesUsecase: flashsalesUsecase,
+	}
+}
+
+// @Summary Find Flash Sales
+// @Description Find flash sales with their products, the ones that have not ended by default
+// @Tags Flash Sales
+// @Accept  json
+// @Produce  json
+// @Param status query string false "Status (running | upcoming | ended)"
+// @Success 200 {array} flashsales.FlashSale
+// @Router /flash-sales [get]
+func (h *flashsalesHandler) FindFlashSale(c fiber.Ctx) error {
+	req := new(flashsales.FlashSaleFilter)
+	if err := c.Bind().Query(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findFlashSaleErr),
+			err.Error(),
+		).Res()
+	}
+
+	result, err := h.flashsalesUsecase.FindFlashSale(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findFlashSaleErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Find One Flash Sale
+// @Description Find a flash sale with its products
+// @Tags Flash Sales
+// @Accept  json
+// @Produce  json
+// @Param flash_sale_id path string true "Flash Sale ID"
+// @Success 200 {object} flashsales.FlashSale
+// @Router /flash-sales/{flash_sale_id} [get]
+func (h *flashsalesHandler) FindOneFlashSale(c fiber.Ctx) error {
+	flashSaleId := strings.Trim(c.Params("flash_sale_id"), " ")
+
+	result, err := h.flashsalesUsecase.FindOneFlashSale(flashSaleId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findOneFlashSaleErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Add Flash Sale
+// @Description Add a flash sale, a product can be in one flash sale at a time
+// @Tags Flash Sales
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param request body flashsales.FlashSaleReq true "Flash Sale Request"
+// @Success 201 {object} flashsales.FlashSale
+// @Router /flash-sales [post]
+func (h *flashsalesHandler) InsertFlashSale(c fiber.Ctx) error {
+	req := new(flashsales.FlashSaleReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertFlashSaleErr),
+			err.Error(),
+		).Res()
+	}
+
+	result, err := h.flashsalesUsecase.InsertFlashSale(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertFlashSaleErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, result).Res()
+}
+
+// @Summary Update Flash Sale
+// @Description Update a flash sale, products replaces its products when it is given
+// @Tags Flash Sales
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param flash_sale_id path string true "Flash Sale ID"
+// @Param request body flashsales.FlashSaleReq true "Flash Sale Request"
+// @Success 200 {object} flashsales.FlashSale
+// @Router /flash-sales/{flash_sale_id} [patch]
+func (h *flashsalesHandler) UpdateFlashSale(c fiber.Ctx) error {
+	req := new(flashsales.FlashSaleReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateFlashSaleErr),
+			err.Error(),
+		).Res()
+	}
+	req.Id = strings.Trim(c.Params("flash_sale_id"), " ")
+
+	result, err := h.flashsalesUsecase.UpdateFlashSale(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateFlashSaleErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Delete Flash Sale
+// @Description Delete a flash sale that has no orders
+// @Tags Flash Sales
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param flash_sale_id path string true "Flash Sale ID"
+// @Success 200
+// @Router /flash-sales/{flash_sale_id} [delete]
+func (h *flashsalesHandler) DeleteFlashSale(c fiber.Ctx) error {
+	flashSaleId := strings.Trim(c.Params("flash_sale_id"), " ")
+
+	if err := h.flashsalesUsecase.DeleteFlashSale(flashSaleId); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(deleteFlashSaleErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, nil).Res()
+}
@@ -0,0 +1,389 @@
+package flashsalesRepositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/flashsales"
+	"github.com/jmoiron/sqlx"
+)
+
+type IFlashsalesRepository interface {
+	FindFlashSale(req *flashsales.FlashSaleFilter) ([]*flashsales.FlashSale, error)
+	FindOneFlashSale(flashSaleId string) (*flashsales.FlashSale, error)
+	InsertFlashSale(req *flashsales.FlashSaleReq) (string, error)
+	UpdateFlashSale(req *flashsales.FlashSaleReq) error
+	DeleteFlashSale(flashSaleId string) error
+}
+
+type flashsalesRepository struct {
+	db *sqlx.DB
+}
+
+func FlashsalesRepository(db *sqlx.DB) IFlashsalesRepository {
+	return &flashsalesRepository{
+		db: db,
+	}
+}
+
+// flashSaleColumns are the columns of a flash sale "f" with its products
+const flashSaleColumns = `
+	"f"."id",
+	"f"."title",
+	"f"."start_at",
+	"f"."end_at",
+	(
+		SELECT
+			COALESCE(array_to_json(array_agg("pt")), '[]'::json)
+		FROM (
+			SELECT
+				"fp"."product_id",
+				"p"."title",
+				"fp"."price",
+				"fp"."qty",
+				"fp"."sold_qty",
+				"fp"."per_customer_limit"
+			FROM "flash_sales_products" "fp"
+				INNER JOIN "products" "p" ON "p"."id" = "fp"."product_id"
+			WHERE "fp"."flash_sale_id" = "f"."id"
+			ORDER BY "p"."title", "fp"."product_id"
+		) AS "pt"
+	) AS "products",
+	"f"."created_at",
+	"f"."updated_at"`
+
+func (r *flashsalesRepository) FindFlashSale(req *flashsales.FlashSaleFilter) ([]*flashsales.FlashSale, error) {
+	where := `"f"."end_at" > now()`
+	if req.Status != "" {
+		where = flashsales.Statuses[req.Status]
+	}
+
+	query := `
+	SELECT
+		COALESCE(array_to_json(array_agg("t")), '[]'::json)
+	FROM (
+		SELECT` + flashSaleColumns + `
+		FROM "flash_sales" "f"
+		WHERE ` + where + `
+		ORDER BY "f"."start_at" DESC
+		LIMIT 100
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query); err != nil {
+		return nil, fmt.Errorf("failed to get flash sales: %w", err)
+	}
+
+	flashSales := make([]*flashsales.FlashSale, 0)
+	if err := json.Unmarshal(raw, &flashSales); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal flash sales: %w", err)
+	}
+	return flashSales, nil
+}
+
+func (r *flashsalesRepository) FindOneFlashSale(flashSaleId string) (*flashsales.FlashSale, error) {
+	query := `
+	SELECT
+		to_json("t")
+	FROM (
+		SELECT` + flashSaleColumns + `
+		FROM "flash_sales" "f"
+		WHERE "f"."id"::TEXT = $1
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, flashSaleId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("flash sale not found")
+		}
+		return nil, fmt.Errorf("failed to get flash sale: %w", err)
+	}
+
+	flashSale := new(flashsales.FlashSale)
+	if err := json.Unmarshal(raw, flashSale); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal flash sale: %w", err)
+	}
+	return flashSale, nil
+}
+
+func (r *flashsalesRepository) InsertFlashSale(req *flashsales.FlashSaleReq) (string, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	query := `
+	INSERT INTO "flash_sales" (
+		"title",
+		"start_at",
+		"end_at"
+	)
+	VALUES ($1, ($2::TIMESTAMPTZ)::TIMESTAMP, ($3::TIMESTAMPTZ)::TIMESTAMP)
+	RETURNING "id"::TEXT;`
+
+	if err := tx.QueryRowxContext(ctx, query, req.Title, req.StartAt, req.EndAt).Scan(&req.Id); err != nil {
+		tx.Rollback()
+		return "", fmt.Errorf("failed to insert flash sale: %w", err)
+	}
+
+	if err := r.replaceProducts(ctx, tx, req); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	return req.Id, nil
+}
+
+func (r *flashsalesRepository) UpdateFlashSale(req *flashsales.FlashSaleReq) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var id string
+	if err := tx.GetContext(ctx, &id, `SELECT "id"::TEXT FROM "flash_sales" WHERE "id"::TEXT = $1 FOR UPDATE;`, req.Id); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("flash sale not found")
+		}
+		return fmt.Errorf("failed to update flash sale: %w", err)
+	}
+
+	query := `
+		UPDATE "flash_sales" SET
+	`
+
+	queryWhereStack := make([]string, 0)
+	values := make([]any, 0)
+	lastIndex := 1
+
+	if req.Title != "" {
+		values = append(values, req.Title)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"title" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.StartAt != "" {
+		values = append(values, req.StartAt)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"start_at" = ($%d::TIMESTAMPTZ)::TIMESTAMP?`, lastIndex))
+		lastIndex++
+	}
+
+	if req.EndAt != "" {
+		values = append(values, req.EndAt)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"end_at" = ($%d::TIMESTAMPTZ)::TIMESTAMP?`, lastIndex))
+		lastIndex++
+	}
+
+	if len(queryWhereStack) > 0 {
+		values = append(values, req.Id)
+
+		queryClose := fmt.Sprintf(` WHERE "id"::TEXT = $%d`, lastIndex)
+
+		for i := range queryWhereStack {
+			if i != len(queryWhereStack)-1 {
+				query += strings.Replace(queryWhereStack[i], "?", ",", 1)
+			} else {
+				query += strings.Replace(queryWhereStack[i], "?", "", 1)
+			}
+		}
+		query += queryClose
+
+		if _, err := tx.ExecContext(ctx, query, values...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update flash sale: %w", err)
+		}
+	}
+
+	if req.Products != nil {
+		if err := r.replaceProducts(ctx, tx, req); err != nil {
+			tx.Rollback()
+			return err
+		}
+	} else if len(queryWhereStack) > 0 {
+		// The window may have moved onto another flash sale
+		productIds := make([]string, 0)
+		if err := tx.SelectContext(ctx, &productIds, `SELECT "product_id" FROM "flash_sales_products" WHERE "flash_sale_id"::TEXT = $1;`, req.Id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update flash sale: %w", err)
+		}
+		if err := lockProducts(ctx, tx, productIds); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := checkOverlap(ctx, tx, req.Id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// replaceProducts sets the products of the flash sale to req.Products, a
+// product that has been sold can't be removed or capped below its sold qty.
+func (r *flashsalesRepository) replaceProducts(ctx context.Context, tx *sqlx.Tx, req *flashsales.FlashSaleReq) error {
+	type soldProduct struct {
+		ProductId string `db:"product_id"`
+		SoldQty   int    `db:"sold_qty"`
+	}
+
+	keep := make([]string, 0, len(req.Products))
+	for _, p := range req.Products {
+		keep = append(keep, p.ProductId)
+	}
+
+	productIds := make([]string, 0)
+	if err := tx.SelectContext(ctx, &productIds, `SELECT "product_id" FROM "flash_sales_products" WHERE "flash_sale_id"::TEXT = $1;`, req.Id); err != nil {
+		return fmt.Errorf("failed to find flash sale products: %w", err)
+	}
+
+	// Products first and then the flash sale rows, the same order as orders
+	if err := lockProducts(ctx, tx, append(productIds, keep...)); err != nil {
+		return err
+	}
+
+	sold := make([]*soldProduct, 0)
+	if err := tx.SelectContext(ctx, &sold, `
+		SELECT
+			"product_id",
+			"sold_qty"
+		FROM "flash_sales_products"
+		WHERE "flash_sale_id"::TEXT = $1
+		FOR UPDATE;`,
+		req.Id,
+	); err != nil {
+		return fmt.Errorf("failed to find flash sale products: %w", err)
+	}
+
+	soldQty := make(map[string]int)
+	for _, p := range sold {
+		if p.SoldQty > 0 && !slices.Contains(keep, p.ProductId) {
+			return fmt.Errorf("product %s has been sold in the flash sale and can't be removed", p.ProductId)
+		}
+		soldQty[p.ProductId] = p.SoldQty
+	}
+	for _, p := range req.Products {
+		if p.Qty < soldQty[p.ProductId] {
+			return fmt.Errorf("qty of product %s can't be less than the %d sold", p.ProductId, soldQty[p.ProductId])
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM "flash_sales_products"
+		WHERE "flash_sale_id"::TEXT = $1
+		AND NOT ("product_id" = ANY($2::VARCHAR[]));`,
+		req.Id,
+		keep,
+	); err != nil {
+		return fmt.Errorf("failed to remove flash sale products: %w", err)
+	}
+
+	query := `
+	INSERT INTO "flash_sales_products" (
+		"flash_sale_id",
+		"product_id",
+		"price",
+		"qty",
+		"per_customer_limit"
+	)
+	VALUES (($1)::uuid, $2, $3, $4, $5)
+	ON CONFLICT ("flash_sale_id", "product_id") DO UPDATE SET
+		"price" = EXCLUDED."price",
+		"qty" = EXCLUDED."qty",
+		"per_customer_limit" = EXCLUDED."per_customer_limit";`
+
+	for _, p := range req.Products {
+		if _, err := tx.ExecContext(ctx, query, req.Id, p.ProductId, p.Price, p.Qty, p.PerCustomerLimit); err != nil {
+			return fmt.Errorf("failed to insert flash sale product %s: %w", p.ProductId, err)
+		}
+	}
+
+	return checkOverlap(ctx, tx, req.Id)
+}
+
+// lockProducts locks the products in id order, so it can't deadlock with
+// the orders that reserve them.
+func lockProducts(ctx context.Context, tx *sqlx.Tx, productIds []string) error {
+	productIds = slices.Clone(productIds)
+	slices.Sort(productIds)
+	for _, productId := range slices.Compact(productIds) {
+		var id string
+		if err := tx.GetContext(ctx, &id, `SELECT "id" FROM "products" WHERE "id" = $1 FOR UPDATE;`, productId); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("product %s not found", productId)
+			}
+			return fmt.Errorf("failed to lock product: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkOverlap makes sure no product of the flash sale is in another flash
+// sale whose window overlaps, the products must be locked.
+func checkOverlap(ctx context.Context, tx *sqlx.Tx, flashSaleId string) error {
+	query := `
+	SELECT
+		"fp"."product_id"
+	FROM "flash_sales_products" "fp"
+		INNER JOIN "flash_sales" "f" ON "f"."id" = "fp"."flash_sale_id"
+		INNER JOIN "flash_sales_products" "ofp" ON "ofp"."product_id" = "fp"."product_id"
+			AND "ofp"."flash_sale_id" != "fp"."flash_sale_id"
+		INNER JOIN "flash_sales" "of" ON "of"."id" = "ofp"."flash_sale_id"
+	WHERE "fp"."flash_sale_id"::TEXT = $1
+	AND "of"."start_at" < "f"."end_at"
+	AND "of"."end_at" > "f"."start_at"
+	LIMIT 1;`
+
+	var productId string
+	if err := tx.GetContext(ctx, &productId, query, flashSaleId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to check flash sale: %w", err)
+	}
+	return fmt.Errorf("product %s is in another flash sale at the same time", productId)
+}
+
+// DeleteFlashSale removes a flash sale that has not sold anything yet, one
+// with orders should be ended early instead.
+func (r *flashsalesRepository) DeleteFlashSale(flashSaleId string) error {
+	query := `
+	DELETE FROM "flash_sales" "f"
+	WHERE "f"."id"::TEXT = $1
+	AND NOT EXISTS (
+		SELECT 1
+		FROM "flash_sales_orders" "fo"
+		WHERE "fo"."flash_sale_id" = "f"."id"
+	)
+	RETURNING "f"."id"::TEXT;`
+
+	var id string
+	if err := r.db.GetContext(context.Background(), &id, query, flashSaleId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := r.FindOneFlashSale(flashSaleId); err != nil {
+				return err
+			}
+			return fmt.Errorf("flash sale has orders, end it instead")
+		}
+		return fmt.Errorf("failed to delete flash sale: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package flashsalesUsecases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/flashsales"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/flashsales/flashsalesRepositories"
+)
+
+type IFlashsalesUsecase interface {
+	FindFlashSale(req *flashsales.FlashSaleFilter) ([]*flashsales.FlashSale, error)
+	FindOneFlashSale(flashSaleId string) (*flashsales.FlashSale, error)
+	InsertFlashSale(req *flashsales.FlashSaleReq) (*flashsales.FlashSale, error)
+	UpdateFlashSale(req *flashsales.FlashSaleReq) (*flashsales.FlashSale, error)
+	DeleteFlashSale(flashSaleId string) error
+}
+
+type flashsalesUsecase struct {
+	flashsalesRepository flashsalesRepositories.IFlashsalesRepository
+}
+
+func FlashsalesUsecase(flashsalesRepository flashsalesRepositories.IFlashsalesRepository) IFlashsalesUsecase {
+	return &flashsalesUsecase{
+		flashsalesRepository: flashsalesRepository,
+	}
+}
+
+func (u *flashsalesUsecase) FindFlashSale(req *flashsales.FlashSaleFilter) ([]*flashsales.FlashSale, error) {
+	if _, ok := flashsales.Statuses[req.Status]; req.Status != "" && !ok {
+		return nil, fmt.Errorf("status is invalid")
+	}
+
+	flashSales, err := u.flashsalesRepository.FindFlashSale(req)
+	if err != nil {
+		return nil, err
+	}
+	return flashSales, nil
+}
+
+func (u *flashsalesUsecase) FindOneFlashSale(flashSaleId string) (*flashsales.FlashSale, error) {
+	flashSale, err := u.flashsalesRepository.FindOneFlashSale(flashSaleId)
+	if err != nil {
+		return nil, err
+	}
+	return flashSale, nil
+}
+
+func (u *flashsalesUsecase) InsertFlashSale(req *flashsales.FlashSaleReq) (*flashsales.FlashSale, error) {
+	if req.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if req.StartAt == "" || req.EndAt == "" {
+		return nil, fmt.Errorf("start_at and end_at are required")
+	}
+	if req.Products == nil {
+		req.Products = make([]*flashsales.FlashSaleProduct, 0)
+	}
+	if err := checkFlashSale(req); err != nil {
+		return nil, err
+	}
+
+	flashSaleId, err := u.flashsalesRepository.InsertFlashSale(req)
+	if err != nil {
+		return nil, err
+	}
+	return u.FindOneFlashSale(flashSaleId)
+}
+
+func (u *flashsalesUsecase) UpdateFlashSale(req *flashsales.FlashSaleReq) (*flashsales.FlashSale, error) {
+	if (req.StartAt == "") != (req.EndAt == "") {
+		return nil, fmt.Errorf("start_at and end_at must be given together")
+	}
+	if err := checkFlashSale(req); err != nil {
+		return nil, err
+	}
+
+	if err := u.flashsalesRepository.UpdateFlashSale(req); err != nil {
+		return nil, err
+	}
+	return u.FindOneFlashSale(req.Id)
+}
+
+func (u *flashsalesUsecase) DeleteFlashSale(flashSaleId string) error {
+	if err := u.flashsalesRepository.DeleteFlashSale(flashSaleId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkFlashSale validates the window when it is given and the products
+func checkFlashSale(req *flashsales.FlashSaleReq) error {
+	if req.StartAt != "" {
+		startAt, err := time.Parse(time.RFC3339, req.StartAt)
+		if err != nil {
+			return fmt.Errorf("start_at must be RFC3339")
+		}
+		endAt, err := time.Parse(time.RFC3339, req.EndAt)
+		if err != nil {
+			return fmt.Errorf("end_at must be RFC3339")
+		}
+		if !endAt.After(startAt) {
+			return fmt.Errorf("end_at must be after start_at")
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range req.Products {
+		if p == nil || p.ProductId == "" {
+			return fmt.Errorf("product_id is required")
+		}
+		if seen[p.ProductId] {
+			return fmt.Errorf("product %s is duplicated", p.ProductId)
+		}
+		seen[p.ProductId] = true
+
+		if p.Price < 0 {
+			return fmt.Errorf("price of product %s must not be negative", p.ProductId)
+		}
+		if p.Qty < 1 {
+			return fmt.Errorf("qty of product %s must be greater than 0", p.ProductId)
+		}
+		if p.PerCustomerLimit != nil && *p.PerCustomerLimit < 1 {
+			return fmt.Errorf("per_customer_limit of product %s must be greater than 0", p.ProductId)
+		}
+	}
+	return nil
+}
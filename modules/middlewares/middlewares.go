@@ -1,6 +1,14 @@
 package middlewares
 
+import "github.com/gofiber/fiber/v3"
+
 type Role struct {
 	Id    int    `db:"id"`
 	Title string `db:"title"`
 }
+
+// IsAdmin tells whether the request comes from an admin, guests have no role
+func IsAdmin(c fiber.Ctx) bool {
+	roleId, ok := c.Locals("userRoleId").(int)
+	return ok && roleId == 2
+}
@@ -28,6 +28,7 @@ type IMiddlewaresHandler interface {
 	RouterCheck() fiber.Handler
 	Logger() fiber.Handler
 	JwtAuth() fiber.Handler
+	OptionalJwtAuth() fiber.Handler
 	ParamsCheck() fiber.Handler
 	Authorize(expectRoleId ...int) fiber.Handler
 	ApiKeyAuth() fiber.Handler
@@ -71,7 +72,7 @@ func (h *middlewaresHandler) Logger() fiber.Handler {
 	return logger.New(logger.Config{
 		Format:     "${time} [${ip}] ${status} - ${method} ${path}\n",
 		TimeFormat: "02/01/2006 15:04:05",
-		TimeZone:   "Asia/Bangkok",
+		TimeZone:   h.cfg.App().TimeZone(),
 	})
 }
 
@@ -104,6 +105,17 @@ func (h *middlewaresHandler) JwtAuth() fiber.Handler {
 	}
 }
 
+// OptionalJwtAuth lets guests through, a request with a token is checked like JwtAuth
+func (h *middlewaresHandler) OptionalJwtAuth() fiber.Handler {
+	jwtAuth := h.JwtAuth()
+	return func(c fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			return c.Next()
+		}
+		return jwtAuth(c)
+	}
+}
+
 func (h *middlewaresHandler) ParamsCheck() fiber.Handler {
 	return func(c fiber.Ctx) error {
 		userId := c.Locals("userId")
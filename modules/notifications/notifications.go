@@ -0,0 +1,10 @@
+package notifications
+
+type Notification struct {
+	Id        string `db:"id" json:"id"`
+	UserId    string `db:"user_id" json:"user_id"`
+	Title     string `db:"title" json:"title"`
+	Message   string `db:"message" json:"message"`
+	IsRead    bool   `db:"is_read" json:"is_read"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+}
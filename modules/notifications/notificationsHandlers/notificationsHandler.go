@@ -0,0 +1,83 @@
+package notificationsHandlers
+
+import (
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/config"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/notifications/notificationsUsecases"
+	"github.com/gofiber/fiber/v3"
+)
+
+type notificationsHandlersErrCode string
+
+const (
+	findNotificationErr notificationsHandlersErrCode = "notifications-001"
+	readNotificationErr notificationsHandlersErrCode = "notifications-002"
+)
+
+type INotificationsHandler interface {
+	FindNotification(c fiber.Ctx) error
+	ReadNotification(c fiber.Ctx) error
+}
+
+type notificationsHandler struct {
+	cfg                  config.IConfig
+	notificationsUsecase notificationsUsecases.INotificationsUsecase
+}
+
+func NotificationsHandler(cfg config.IConfig, notificationsUsecase notificationsUsecases.INotificationsUsecase) INotificationsHandler {
+	return &notificationsHandler{
+		cfg:                  cfg,
+		notificationsUsecase: notificationsUsecase,
+	}
+}
+
+// @Summary Find Notifications
+// @Description Find latest notifications of a user
+// @Tags Notifications
+// @Accept  json
+// @Produce  json
+// @Param user_id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {array} notifications.Notification
+// @Router /users/{user_id}/notifications [get]
+func (h *notificationsHandler) FindNotification(c fiber.Ctx) error {
+	userId := strings.Trim(c.Params("user_id"), " ")
+
+	result, err := h.notificationsUsecase.FindNotification(userId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findNotificationErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Read Notification
+// @Description Mark a notification as read
+// @Tags Notifications
+// @Accept  json
+// @Produce  json
+// @Param user_id path string true "User ID"
+// @Param notification_id path string true "Notification ID"
+// @Security BearerAuth
+// @Success 200
+// @Router /users/{user_id}/notifications/{notification_id} [patch]
+func (h *notificationsHandler) ReadNotification(c fiber.Ctx) error {
+	userId := strings.Trim(c.Params("user_id"), " ")
+	notificationId := strings.Trim(c.Params("notification_id"), " ")
+
+	if err := h.notificationsUsecase.ReadNotification(userId, notificationId); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(readNotificationErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, nil).Res()
+}
@@ -0,0 +1,85 @@
+package notificationsRepositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/notifications"
+	"github.com/jmoiron/sqlx"
+)
+
+type INotificationsRepository interface {
+	FindNotification(userId string) ([]*notifications.Notification, error)
+	InsertNotification(req *notifications.Notification) error
+	ReadNotification(userId, notificationId string) error
+}
+
+type notificationsRepository struct {
+	db *sqlx.DB
+}
+
+func NotificationsRepository(db *sqlx.DB) INotificationsRepository {
+	return &notificationsRepository{
+		db: db,
+	}
+}
+
+func (r *notificationsRepository) FindNotification(userId string) ([]*notifications.Notification, error) {
+	query := `
+	SELECT
+		"id",
+		"user_id",
+		"title",
+		"message",
+		"is_read",
+		"created_at"
+	FROM "notifications"
+	WHERE "user_id" = $1
+	ORDER BY "created_at" DESC
+	LIMIT 100;`
+
+	notificationsData := make([]*notifications.Notification, 0)
+	if err := r.db.Select(&notificationsData, query, userId); err != nil {
+		return nil, fmt.Errorf("notifications are not found")
+	}
+	return notificationsData, nil
+}
+
+func (r *notificationsRepository) InsertNotification(req *notifications.Notification) error {
+	query := `
+	INSERT INTO "notifications" (
+		"user_id",
+		"title",
+		"message"
+	)
+	VALUES ($1, $2, $3)
+	RETURNING "id";`
+
+	if err := r.db.QueryRowxContext(
+		context.Background(),
+		query,
+		req.UserId,
+		req.Title,
+		req.Message,
+	).Scan(&req.Id); err != nil {
+		return fmt.Errorf("failed to insert notification: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationsRepository) ReadNotification(userId, notificationId string) error {
+	query := `
+	UPDATE "notifications" SET
+		"is_read" = TRUE
+	WHERE "id" = $1
+	AND "user_id" = $2;`
+
+	result, err := r.db.ExecContext(context.Background(), query, notificationId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to read notification: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package notificationsUsecases
+
+import (
+	"github.com/IzePhanthakarn/go-basic-shop/modules/notifications"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/notifications/notificationsRepositories"
+)
+
+type INotificationsUsecase interface {
+	FindNotification(userId string) ([]*notifications.Notification, error)
+	ReadNotification(userId, notificationId string) error
+}
+
+type notificationsUsecase struct {
+	notificationsRepository notificationsRepositories.INotificationsRepository
+}
+
+func NotificationsUsecase(notificationsRepository notificationsRepositories.INotificationsRepository) INotificationsUsecase {
+	return &notificationsUsecase{
+		notificationsRepository: notificationsRepository,
+	}
+}
+
+func (u *notificationsUsecase) FindNotification(userId string) ([]*notifications.Notification, error) {
+	notificationsData, err := u.notificationsRepository.FindNotification(userId)
+	if err != nil {
+		return nil, err
+	}
+	return notificationsData, nil
+}
+
+func (u *notificationsUsecase) ReadNotification(userId, notificationId string) error {
+	if err := u.notificationsRepository.ReadNotification(userId, notificationId); err != nil {
+		return err
+	}
+	return nil
+}
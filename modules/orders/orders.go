@@ -6,16 +6,36 @@ import (
 )
 
 type OrderFilter struct {
-	Search    string `query:"search"` // Search by user_id, address, contact
+	Search    string `query:"search"` // Search by number, address, contact
+	UserId    string `query:"user_id"`
 	Status    string `query:"status"`
 	StartDate string `query:"start_date"`
 	EndDate   string `query:"end_date"`
 	*entities.PaginationReq
 	*entities.SortReq
+	*entities.CursorReq
+}
+
+// SortFields are the fields that sort accepts
+var SortFields = map[string]string{
+	"id":         `"o"."id"`,
+	"number":     `"o"."number"`,
+	"status":     `"o"."status"`,
+	"total_paid": `"total_paid"`,
+	"created_at": `"o"."created_at"`,
+	"updated_at": `"o"."updated_at"`,
+}
+
+// CursorOrderBy are the orders that the cursor mode supports, with the SQL type of their value
+var CursorOrderBy = map[string]string{
+	"id":         "VARCHAR",
+	"number":     "VARCHAR",
+	"created_at": "TIMESTAMP",
 }
 
 type Order struct {
 	Id           string           `db:"id" json:"id"`
+	Number       string           `db:"number" json:"number"`
 	UserId       string           `db:"user_id" json:"user_id"`
 	TransferSlip *TransferSlip    `db:"transfer_slip" json:"transfer_slip"`
 	Products     []*ProductsOrder `json:"products"`
@@ -23,6 +43,7 @@ type Order struct {
 	Contact      string           `db:"contact" json:"contact"`
 	Status       string           `db:"status" json:"status"`
 	TotalPaid    float64          `db:"total_paid" json:"total_paid"`
+	Timeline     []*Timeline      `json:"timeline,omitempty"`
 	CreatedAt    string           `db:"created_at" json:"created_at"`
 	UpdatedAt    string           `db:"updated_at" json:"updated_at"`
 }
@@ -46,4 +67,34 @@ type ProductsOrder struct {
 	Id      string            `db:"id" json:"id"`
 	Qty     int               `db:"qty" json:"qty"`
 	Product *products.Product `db:"product" json:"product"`
+	Variant *products.Variant `db:"variant" json:"variant,omitempty"`
+}
+
+type Timeline struct {
+	Id        string `db:"id" json:"id"`
+	Event     string `db:"event" json:"event"`
+	Message   string `db:"message" json:"message"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+}
+
+type Message struct {
+	Id          string        `db:"id" json:"id"`
+	OrderId     string        `db:"order_id" json:"order_id"`
+	UserId      string        `db:"user_id" json:"user_id"`
+	Message     string        `db:"message" json:"message"`
+	Attachments []*Attachment `db:"attachments" json:"attachments"`
+	IsInternal  bool          `db:"is_internal" json:"is_internal"` // admin only note
+	CreatedAt   string        `db:"created_at" json:"created_at"`
+}
+
+type MessageReq struct {
+	Message     string        `json:"message"`
+	Attachments []*Attachment `json:"attachments"`
+	IsInternal  bool          `json:"is_internal"`
+}
+
+type Attachment struct {
+	Id       string `json:"id"`
+	Filename string `json:"filename"`
+	Url      string `json:"url"`
 }
@@ -1,6 +1,7 @@
 package ordersHandlers
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -15,17 +16,23 @@ import (
 type ordersHandlersErrCode string
 
 const (
-	findOneOrderErr ordersHandlersErrCode = "orders-001"
-	findOrderErr    ordersHandlersErrCode = "orders-002"
-	insertOrderErr  ordersHandlersErrCode = "orders-003"
-	updateOrderErr  ordersHandlersErrCode = "orders-004"
+	findOneOrderErr  ordersHandlersErrCode = "orders-001"
+	findOrderErr     ordersHandlersErrCode = "orders-002"
+	insertOrderErr   ordersHandlersErrCode = "orders-003"
+	updateOrderErr   ordersHandlersErrCode = "orders-004"
+	findUserOrderErr ordersHandlersErrCode = "orders-005"
+	findMessageErr   ordersHandlersErrCode = "orders-006"
+	insertMessageErr ordersHandlersErrCode = "orders-007"
 )
 
 type IOrdersHandler interface {
 	FindOneOrder(c fiber.Ctx) error
 	FindOrder(c fiber.Ctx) error
+	FindUserOrder(c fiber.Ctx) error
 	InsertOrder(c fiber.Ctx) error
 	UpdateOrder(c fiber.Ctx) error
+	FindMessage(c fiber.Ctx) error
+	InsertMessage(c fiber.Ctx) error
 }
 
 type ordersHandlers struct {
@@ -66,34 +73,103 @@ func (h *ordersHandlers) FindOneOrder(c fiber.Ctx) error {
 }
 
 // @Summary Find Orders
-// @Description Find Orders
+// @Description Find Orders, customers only get their own orders
 // @Tags Orders
 // @Accept  json
 // @Produce  json
 // @Param page query int false "Page" default(1)
 // @Param limit query int false "Limit" default(10)
-// @Param order_by query string false "Order By field" default(id)
-// @Param sort_by query string false "Sort By direction (asc or desc)" default(desc)
-// @Param search query string false "Search by user_id | address | contact"
+// @Param sort query string false "Sort fields, e.g. created_at:desc,number:asc, fields are id | number | status | total_paid | created_at | updated_at" default(id:desc)
+// @Param order_by query string false "Order By field, used when sort is not sent"
+// @Param sort_by query string false "Sort By direction (asc or desc), used when sort is not sent"
+// @Param search query string false "Search by number | address | contact"
+// @Param user_id query string false "User ID (admin only)"
 // @Param status query string false "Status"
 // @Param start_date query string false "Start Date (YYYY-MM-DD)"
 // @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Param cursor query bool false "Cursor pagination instead of page, responds entities.CursorRes, order_by is id | number | created_at"
+// @Param after query string false "Next page cursor"
+// @Param before query string false "Previous page cursor"
+// @Param with_total query bool false "Count total items in cursor mode"
 // @Security BearerAuth
 // @Success 200 {object} entities.PaginateRes
 // @Router /orders [get]
 func (h *ordersHandlers) FindOrder(c fiber.Ctx) error {
-	req := &orders.OrderFilter{
-		SortReq:       &entities.SortReq{},
-		PaginationReq: &entities.PaginationReq{},
+	req, err := h.orderFilter(c)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findOrderErr),
+			err.Error(),
+		).Res()
 	}
 
-	if err := c.Bind().Query(req); err != nil {
+	// Customers are always scoped to their own orders
+	if c.Locals("userRoleId").(int) != 2 {
+		req.UserId = strings.Trim(c.Locals("userId").(string), " ")
+	}
+
+	if req.IsCursor() {
+		return entities.NewResponse(c).Success(fiber.StatusOK, h.orderUsecase.FindOrderCursor(req)).Res()
+	}
+
+	orders := h.orderUsecase.FindOrder(req)
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, orders).Res()
+}
+
+// @Summary Find User Orders
+// @Description Find Orders of a user
+// @Tags Orders
+// @Accept  json
+// @Produce  json
+// @Param user_id path string true "User ID"
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(10)
+// @Param sort query string false "Sort fields, e.g. created_at:desc,number:asc, fields are id | number | status | total_paid | created_at | updated_at" default(id:desc)
+// @Param order_by query string false "Order By field, used when sort is not sent"
+// @Param sort_by query string false "Sort By direction (asc or desc), used when sort is not sent"
+// @Param search query string false "Search by number | address | contact"
+// @Param status query string false "Status"
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Param cursor query bool false "Cursor pagination instead of page, responds entities.CursorRes, order_by is id | number | created_at"
+// @Param after query string false "Next page cursor"
+// @Param before query string false "Previous page cursor"
+// @Param with_total query bool false "Count total items in cursor mode"
+// @Security BearerAuth
+// @Success 200 {object} entities.PaginateRes
+// @Router /users/{user_id}/orders [get]
+func (h *ordersHandlers) FindUserOrder(c fiber.Ctx) error {
+	req, err := h.orderFilter(c)
+	if err != nil {
 		return entities.NewResponse(c).Error(
 			fiber.StatusBadRequest,
-			string(findOrderErr),
+			string(findUserOrderErr),
 			err.Error(),
 		).Res()
 	}
+	req.UserId = strings.Trim(c.Params("user_id"), " ")
+
+	if req.IsCursor() {
+		return entities.NewResponse(c).Success(fiber.StatusOK, h.orderUsecase.FindOrderCursor(req)).Res()
+	}
+
+	orders := h.orderUsecase.FindOrder(req)
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, orders).Res()
+}
+
+func (h *ordersHandlers) orderFilter(c fiber.Ctx) (*orders.OrderFilter, error) {
+	req := &orders.OrderFilter{
+		SortReq:       &entities.SortReq{},
+		PaginationReq: &entities.PaginationReq{},
+		CursorReq:     &entities.CursorReq{},
+	}
+
+	if err := c.Bind().Query(req); err != nil {
+		return nil, err
+	}
 
 	if req.Page < 1 {
 		req.Page = 1
@@ -103,31 +179,29 @@ func (h *ordersHandlers) FindOrder(c fiber.Ctx) error {
 		req.Limit = 5
 	}
 
-	orderByMap := map[string]string{
-		"id":         `"o"."id"`,
-		"created_at": `"o"."created_at"`,
-	}
-	if orderByMap[req.SortBy] == "" {
-		req.OrderBy = orderByMap["id"]
-	}
+	if req.IsCursor() {
+		// Cursor mode sorts by a single field
+		if err := req.SortReq.Parse(orders.CursorOrderBy, "id:desc"); err != nil || len(req.Fields) != 1 {
+			return nil, fmt.Errorf("sort is not supported with cursor")
+		}
 
-	req.SortBy = strings.ToUpper(req.SortBy)
-	sortMap := map[string]string{
-		"ASC":  "ASC",
-		"DESC": "DESC",
-	}
-	if sortMap[req.SortBy] != "" {
-		req.SortBy = sortMap["DESC"]
+		req.OrderBy = req.Fields[0].Field
+		req.SortBy = "ASC"
+		if req.Fields[0].Desc {
+			req.SortBy = "DESC"
+		}
+
+		if err := req.CursorReq.Decode(req.OrderBy, req.SortBy); err != nil {
+			return nil, err
+		}
+	} else if err := req.SortReq.Parse(orders.SortFields, "id:desc"); err != nil {
+		return nil, err
 	}
 
 	if req.StartDate != "" {
 		start, err := time.Parse("2006-01-02", req.StartDate)
 		if err != nil {
-			return entities.NewResponse(c).Error(
-				fiber.StatusBadRequest,
-				string(findOrderErr),
-				"invalid start date",
-			).Res()
+			return nil, fmt.Errorf("invalid start date")
 		}
 		req.StartDate = start.Format("2006-01-02")
 	}
@@ -135,18 +209,12 @@ func (h *ordersHandlers) FindOrder(c fiber.Ctx) error {
 	if req.EndDate != "" {
 		end, err := time.Parse("2006-01-02", req.EndDate)
 		if err != nil {
-			return entities.NewResponse(c).Error(
-				fiber.StatusBadRequest,
-				string(findOrderErr),
-				"invalid end date",
-			).Res()
+			return nil, fmt.Errorf("invalid end date")
 		}
 		req.EndDate = end.Format("2006-01-02")
 	}
 
-	orders := h.orderUsecase.FindOrder(req)
-
-	return entities.NewResponse(c).Success(fiber.StatusOK, orders).Res()
+	return req, nil
 }
 
 // @Summary Insert Order
@@ -232,6 +300,15 @@ func (h *ordersHandlers) UpdateOrder(c fiber.Ctx) error {
 
 	if c.Locals("userRoleId").(int) != 2 {
 		req.UserId = strings.Trim(c.Locals("userId").(string), " ")
+
+		// Customers can only cancel, shipping and completing is up to the admins
+		if req.Status != "" && req.Status != statusMap["canceled"] {
+			return entities.NewResponse(c).Error(
+				fiber.StatusForbidden,
+				string(updateOrderErr),
+				"customers can only cancel an order",
+			).Res()
+		}
 	} else if strings.ToLower(req.Status) == statusMap["canceles"] {
 		req.Status = statusMap["canceled"]
 	}
@@ -241,15 +318,7 @@ func (h *ordersHandlers) UpdateOrder(c fiber.Ctx) error {
 			req.TransferSlip.Id = uuid.NewString()
 		}
 		if req.TransferSlip.CreatedAt == "" {
-			loc, err := time.LoadLocation("Asia/Bangkok")
-			if err != nil {
-				return entities.NewResponse(c).Error(
-					fiber.StatusInternalServerError,
-					string(updateOrderErr),
-					err.Error(),
-				).Res()
-			}
-			now := time.Now().In(loc)
+			now := time.Now().In(h.cfg.App().Location())
 
 			// YYYY-MM-DD HH:MM:SS
 			// 2006-01-02 15:04:05
@@ -268,3 +337,95 @@ func (h *ordersHandlers) UpdateOrder(c fiber.Ctx) error {
 
 	return entities.NewResponse(c).Success(fiber.StatusOK, order).Res()
 }
+
+// @Summary Find Order Messages
+// @Description Find messages of an order, internal notes are only returned to admins
+// @Tags Orders
+// @Accept  json
+// @Produce  json
+// @Param user_id path string true "User ID"
+// @Param order_id path string true "Order ID"
+// @Security BearerAuth
+// @Success 200 {array} orders.Message
+// @Router /orders/{user_id}/{order_id}/messages [get]
+func (h *ordersHandlers) FindMessage(c fiber.Ctx) error {
+	userId := strings.Trim(c.Params("user_id"), " ")
+	orderId := strings.Trim(c.Params("order_id"), " ")
+	isAdmin := c.Locals("userRoleId").(int) == 2
+
+	messages, err := h.orderUsecase.FindMessage(userId, orderId, isAdmin)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findMessageErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, messages).Res()
+}
+
+// @Summary Insert Order Message
+// @Description Post a message on an order, only admins can post internal notes
+// @Tags Orders
+// @Accept  json
+// @Produce  json
+// @Param user_id path string true "User ID"
+// @Param order_id path string true "Order ID"
+// @Param request body orders.MessageReq true "Message Request"
+// @Security BearerAuth
+// @Success 201 {object} orders.Message
+// @Router /orders/{user_id}/{order_id}/messages [post]
+func (h *ordersHandlers) InsertMessage(c fiber.Ctx) error {
+	userId := strings.Trim(c.Params("user_id"), " ")
+
+	req := &orders.Message{
+		Attachments: make([]*orders.Attachment, 0),
+	}
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertMessageErr),
+			err.Error(),
+		).Res()
+	}
+	req.Message = strings.TrimSpace(req.Message)
+
+	if req.Message == "" && len(req.Attachments) == 0 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertMessageErr),
+			"message is empty",
+		).Res()
+	}
+
+	for i := range req.Attachments {
+		if req.Attachments[i] == nil || req.Attachments[i].Url == "" {
+			return entities.NewResponse(c).Error(
+				fiber.StatusBadRequest,
+				string(insertMessageErr),
+				"attachment url is required",
+			).Res()
+		}
+		if req.Attachments[i].Id == "" {
+			req.Attachments[i].Id = uuid.NewString()
+		}
+	}
+
+	req.OrderId = strings.Trim(c.Params("order_id"), " ")
+	req.UserId = strings.Trim(c.Locals("userId").(string), " ")
+	if c.Locals("userRoleId").(int) != 2 {
+		req.IsInternal = false
+	}
+
+	message, err := h.orderUsecase.InsertMessage(userId, req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertMessageErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, message).Res()
+}
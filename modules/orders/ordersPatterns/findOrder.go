@@ -15,11 +15,13 @@ import (
 type IFindOrderBuilder interface {
 	initQuery()
 	initCountQuery()
+	buildWhereUserId()
 	buildWhereSearch()
 	buildWhereStatus()
 	buildWhereDate()
 	buildSort()
 	buildPaginate()
+	buildCursor()
 	closeQuery()
 	getQuery() string
 	setQuery(query string)
@@ -63,6 +65,7 @@ func (b *findOrderBuilder) initQuery() {
 		FROM (
 			SELECT
 				"o"."id",
+				"o"."number",
 				"o"."user_id",
 				"o"."transfer_slip",
 				(
@@ -72,7 +75,8 @@ func (b *findOrderBuilder) initQuery() {
 						SELECT
 							"spo"."id",
 							"spo"."qty",
-							"spo"."product"
+							"spo"."product",
+							"spo"."variant"
 						FROM "products_orders" "spo"
 						WHERE "spo"."order_id" = "o"."id"
 					) AS "pt"
@@ -102,6 +106,25 @@ func (b *findOrderBuilder) initCountQuery() {
 	`
 }
 
+func (b *findOrderBuilder) buildWhereUserId() {
+	if b.req.UserId != "" {
+		b.values = append(
+			b.values,
+			b.req.UserId,
+		)
+
+		query := fmt.Sprintf(`
+			AND "o"."user_id" = $%d`,
+			b.lastIndex+1,
+		)
+		temp := b.getQuery()
+		temp += query
+		b.setQuery(temp)
+
+		b.lastIndex = len(b.values)
+	}
+}
+
 func (b *findOrderBuilder) buildWhereSearch() {
 	if b.req.Search != "" {
 		b.values = append(
@@ -109,10 +132,12 @@ func (b *findOrderBuilder) buildWhereSearch() {
 			"%"+strings.ToLower(b.req.Search)+"%",
 			"%"+strings.ToLower(b.req.Search)+"%",
 			"%"+strings.ToLower(b.req.Search)+"%",
+			"%"+strings.ToLower(b.req.Search)+"%",
 		)
 
 		query := fmt.Sprintf(`
 			AND (
+				LOWER("o"."number") LIKE $%d OR
 				LOWER(("o"."transfer_slip")::text) LIKE $%d OR
 				LOWER(("o"."address")::text) LIKE $%d OR
 				LOWER(("o"."contact")::text) LIKE $%d
@@ -120,6 +145,7 @@ func (b *findOrderBuilder) buildWhereSearch() {
 			b.lastIndex+1,
 			b.lastIndex+2,
 			b.lastIndex+3,
+			b.lastIndex+4,
 		)
 		temp := b.getQuery()
 		temp += query
@@ -169,16 +195,8 @@ func (b *findOrderBuilder) buildWhereDate() {
 }
 
 func (b *findOrderBuilder) buildSort() {
-	// sanitize order by (ป้องกัน SQL injection)
-	orderBy := "o.id"
-	if b.req.OrderBy != "" {
-		orderBy = b.req.OrderBy
-	}
-	sortBy := "ASC"
-	if strings.ToUpper(b.req.SortBy) == "DESC" {
-		sortBy = "DESC"
-	}
-	b.query += fmt.Sprintf(` ORDER BY %s %s`, orderBy, sortBy)
+	// Only whitelisted columns reach the SQL (ป้องกัน SQL injection)
+	b.query += b.req.SortReq.Query(orders.SortFields, `"o"."id" ASC`)
 }
 
 func (b *findOrderBuilder) buildPaginate() {
@@ -197,6 +215,48 @@ func (b *findOrderBuilder) buildPaginate() {
 	b.lastIndex = len(b.values)
 }
 
+// buildCursor replaces buildSort and buildPaginate in cursor mode, it takes
+// one more row to know if there is another page.
+func (b *findOrderBuilder) buildCursor() {
+	column := `"o"."` + b.req.OrderBy + `"`
+
+	sortBy, compare := "ASC", ">"
+	if strings.ToUpper(b.req.SortBy) == "DESC" {
+		sortBy, compare = "DESC", "<"
+	}
+	// A before page walks backward, the rows are reversed later
+	if b.req.IsBefore() {
+		if sortBy == "ASC" {
+			sortBy, compare = "DESC", "<"
+		} else {
+			sortBy, compare = "ASC", ">"
+		}
+	}
+
+	if b.req.Key != nil {
+		b.values = append(b.values, b.req.Key.Value, b.req.Key.Id)
+		b.query += fmt.Sprintf(`
+			AND (%s, "o"."id") %s (($%d)::TEXT::%s, $%d)`,
+			column,
+			compare,
+			b.lastIndex+1,
+			orders.CursorOrderBy[b.req.OrderBy],
+			b.lastIndex+2,
+		)
+	}
+
+	b.values = append(b.values, b.req.Limit+1)
+	b.query += fmt.Sprintf(`
+		ORDER BY %s %s, "o"."id" %s
+		LIMIT $%d`,
+		column,
+		sortBy,
+		sortBy,
+		len(b.values),
+	)
+	b.lastIndex = len(b.values)
+}
+
 func (b *findOrderBuilder) closeQuery() {
 	b.query += `
 	) AS "at"`
@@ -237,6 +297,7 @@ func (en *findOrderEngineer) FindOrder() []*orders.Order {
 	defer cancel()
 
 	en.builder.initQuery()
+	en.builder.buildWhereUserId()
 	en.builder.buildWhereSearch()
 	en.builder.buildWhereStatus()
 	en.builder.buildWhereDate()
@@ -244,6 +305,22 @@ func (en *findOrderEngineer) FindOrder() []*orders.Order {
 	en.builder.buildPaginate()
 	en.builder.closeQuery()
 
+	return en.result()
+}
+
+func (en *findOrderEngineer) FindOrderCursor() []*orders.Order {
+	en.builder.initQuery()
+	en.builder.buildWhereUserId()
+	en.builder.buildWhereSearch()
+	en.builder.buildWhereStatus()
+	en.builder.buildWhereDate()
+	en.builder.buildCursor()
+	en.builder.closeQuery()
+
+	return en.result()
+}
+
+func (en *findOrderEngineer) result() []*orders.Order {
 	raw := make([]byte, 0)
 	if err := en.builder.getDb().Get(&raw, en.builder.getQuery(), en.builder.getValues()...); err != nil {
 		log.Printf("Error find order: %v", err)
@@ -272,6 +349,7 @@ func (en *findOrderEngineer) CountOrder() int {
 
 	en.builder.reset()
 	en.builder.initCountQuery()
+	en.builder.buildWhereUserId()
 	en.builder.buildWhereSearch()
 	en.builder.buildWhereStatus()
 	en.builder.buildWhereDate()
@@ -1,9 +1,15 @@
 package ordersPatterns
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/IzePhanthakarn/go-basic-shop/config"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/orders"
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/net/context"
@@ -11,8 +17,11 @@ import (
 
 type IInsertOrderBuilder interface {
 	initTransaction() error
+	generateOrderNumber() error
 	insertOrder() error
 	insertProductsOrder() error
+	reserveStock() error
+	reserveFlashSale() error
 	getOrderId() string
 	commit() error
 }
@@ -21,6 +30,7 @@ type insertOrderBuilder struct {
 	req *orders.Order
 	db  *sqlx.DB
 	tx  *sqlx.Tx
+	cfg config.IConfig
 }
 
 type insertOrderEngineer struct {
@@ -36,25 +46,65 @@ func (b *insertOrderBuilder) initTransaction() error {
 	return nil
 }
 
+func (b *insertOrderBuilder) generateOrderNumber() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	// The year of the number is the year in the app timezone
+	now := time.Now().In(b.cfg.App().Location())
+	period := "all"
+	if b.cfg.Order().NumberResetYearly() {
+		period = strconv.Itoa(now.Year())
+	}
+
+	// The counter row stays locked until commit, a rollback gives the number back
+	query := `
+		INSERT INTO "orders_numbers" (
+			"period",
+			"last_value"
+		)
+		VALUES ($1, 1)
+		ON CONFLICT ("period") DO UPDATE SET
+			"last_value" = "orders_numbers"."last_value" + 1
+		RETURNING "last_value";
+	`
+
+	var seq int64
+	if err := b.tx.QueryRowxContext(ctx, query, period).Scan(&seq); err != nil {
+		b.tx.Rollback()
+		return fmt.Errorf("failed to generate order number: %w", err)
+	}
+
+	b.req.Number = strings.NewReplacer(
+		"{YYYY}", strconv.Itoa(now.Year()),
+		"{YY}", fmt.Sprintf("%02d", now.Year()%100),
+		"{SEQ}", fmt.Sprintf("%0*d", b.cfg.Order().NumberPadding(), seq),
+	).Replace(b.cfg.Order().NumberFormat())
+
+	return nil
+}
+
 func (b *insertOrderBuilder) insertOrder() error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
 	defer cancel()
 
 	query := `
 		INSERT INTO "orders" (
+			"number",
 			"user_id",
 			"contact",
 			"address",
 			"transfer_slip",
 			"status"
 		)
-		VALUES ($1, $2, $3, $4, $5)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING "id";	
 	`
 
 	if err := b.tx.QueryRowxContext(
 		ctx,
 		query,
+		b.req.Number,
 		b.req.UserId,
 		b.req.Contact,
 		b.req.Address,
@@ -76,7 +126,8 @@ func (b *insertOrderBuilder) insertProductsOrder() error {
 		INSERT INTO "products_orders" (
 			"order_id",
 			"qty",
-			"product"
+			"product",
+			"variant"
 		)
 		VALUES
 	`
@@ -88,14 +139,15 @@ func (b *insertOrderBuilder) insertProductsOrder() error {
 			values, b.req.Id,
 			b.req.Products[i].Qty,
 			b.req.Products[i].Product,
+			b.req.Products[i].Variant,
 		)
 
 		if i != len(b.req.Products)-1 {
-			query += fmt.Sprintf(`($%d, $%d, $%d),`, lastIndex+1, lastIndex+2, lastIndex+3)
+			query += fmt.Sprintf(`($%d, $%d, $%d, $%d),`, lastIndex+1, lastIndex+2, lastIndex+3, lastIndex+4)
 		} else {
-			query += fmt.Sprintf(`($%d, $%d, $%d);`, lastIndex+1, lastIndex+2, lastIndex+3)
+			query += fmt.Sprintf(`($%d, $%d, $%d, $%d);`, lastIndex+1, lastIndex+2, lastIndex+3, lastIndex+4)
 		}
-		lastIndex += 3
+		lastIndex += 4
 
 	}
 
@@ -107,6 +159,244 @@ func (b *insertOrderBuilder) insertProductsOrder() error {
 	return nil
 }
 
+func (b *insertOrderBuilder) reserveStock() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	// Sum qty per product and variant
+	type stockKey struct {
+		productId string
+		variantId string
+	}
+	qtyMap := make(map[stockKey]int)
+	for i := range b.req.Products {
+		key := stockKey{productId: b.req.Products[i].Product.Id}
+		if b.req.Products[i].Variant != nil {
+			key.variantId = b.req.Products[i].Variant.Id
+		}
+		qtyMap[key] += b.req.Products[i].Qty
+	}
+	keys := make([]stockKey, 0, len(qtyMap))
+	for key := range qtyMap {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].productId != keys[j].productId {
+			return keys[i].productId < keys[j].productId
+		}
+		return keys[i].variantId < keys[j].variantId
+	})
+
+	// Lock products in id order first, the variants stock trigger updates the
+	// product row too, so concurrent orders can't deadlock
+	queryLock := `SELECT "stock" IS NULL FROM "products" WHERE "id" = $1 FOR UPDATE;`
+	untracked := make(map[string]bool)
+	for i, key := range keys {
+		if i > 0 && keys[i-1].productId == key.productId {
+			continue
+		}
+		var isUntracked bool
+		if err := b.tx.GetContext(ctx, &isUntracked, queryLock, key.productId); err != nil {
+			b.tx.Rollback()
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("product %s not found", key.productId)
+			}
+			return fmt.Errorf("failed to reserve stock: %w", err)
+		}
+		untracked[key.productId] = isUntracked
+	}
+
+	queryProduct := `
+		WITH "reserved" AS (
+			UPDATE "products" SET
+				"stock" = "stock" - $1
+			WHERE "id" = $2
+			AND "stock" >= $1
+			RETURNING "id"
+		)
+		INSERT INTO "products_stocks" (
+			"product_id",
+			"qty",
+			"reason",
+			"order_id",
+			"user_id"
+		)
+		SELECT
+			"r"."id",
+			-($1)::INT,
+			'order placed',
+			$3,
+			$4
+		FROM "reserved" "r";
+	`
+
+	queryVariant := `
+		WITH "reserved" AS (
+			UPDATE "products_variants" SET
+				"stock" = "stock" - $1
+			WHERE "id" = $5
+			AND "product_id" = $2
+			AND "deleted_at" IS NULL
+			AND "stock" >= $1
+			RETURNING "id", "product_id"
+		)
+		INSERT INTO "products_stocks" (
+			"product_id",
+			"variant_id",
+			"qty",
+			"reason",
+			"order_id",
+			"user_id"
+		)
+		SELECT
+			"r"."product_id",
+			"r"."id",
+			-($1)::INT,
+			'order placed',
+			$3,
+			$4
+		FROM "reserved" "r";
+	`
+
+	for _, key := range keys {
+		// Nothing is reserved or released for a stock that isn't tracked
+		if key.variantId == "" && untracked[key.productId] {
+			continue
+		}
+
+		query := queryProduct
+		values := []any{qtyMap[key], key.productId, b.req.Id, b.req.UserId}
+		if key.variantId != "" {
+			query = queryVariant
+			values = append(values, key.variantId)
+		}
+
+		result, err := b.tx.ExecContext(ctx, query, values...)
+		if err != nil {
+			b.tx.Rollback()
+			return fmt.Errorf("failed to reserve stock: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			b.tx.Rollback()
+			if key.variantId != "" {
+				return fmt.Errorf("variant %s of product %s is out of stock", key.variantId, key.productId)
+			}
+			return fmt.Errorf("product %s is out of stock", key.productId)
+		}
+	}
+
+	return nil
+}
+
+// reserveFlashSale counts the ordered qty against the flash sale cap and the
+// per customer limit, it runs after reserveStock so the products are locked
+// and orders of the same product can't pass the limits together.
+func (b *insertOrderBuilder) reserveFlashSale() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	type flashSaleKey struct {
+		flashSaleId string
+		productId   string
+	}
+	qtyMap := make(map[flashSaleKey]int)
+	keys := make([]flashSaleKey, 0)
+	for i := range b.req.Products {
+		if b.req.Products[i].Product.FlashSale == nil {
+			continue
+		}
+		key := flashSaleKey{
+			flashSaleId: b.req.Products[i].Product.FlashSale.Id,
+			productId:   b.req.Products[i].Product.Id,
+		}
+		if _, ok := qtyMap[key]; !ok {
+			keys = append(keys, key)
+		}
+		qtyMap[key] += b.req.Products[i].Qty
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].productId < keys[j].productId
+	})
+
+	queryCheck := `
+		SELECT
+			("f"."start_at" <= now() AND "f"."end_at" > now()) AS "running",
+			"fp"."qty" - "fp"."sold_qty" AS "left_qty",
+			"fp"."per_customer_limit" - (
+				SELECT
+					COALESCE(SUM("fo"."qty"), 0)
+				FROM "flash_sales_orders" "fo"
+				WHERE "fo"."flash_sale_id" = "fp"."flash_sale_id"
+				AND "fo"."product_id" = "fp"."product_id"
+				AND "fo"."user_id" = $3
+			) AS "customer_left_qty"
+		FROM "flash_sales_products" "fp"
+			INNER JOIN "flash_sales" "f" ON "f"."id" = "fp"."flash_sale_id"
+		WHERE "fp"."flash_sale_id" = $1
+		AND "fp"."product_id" = $2
+		FOR UPDATE OF "fp";
+	`
+
+	queryReserve := `
+		WITH "reserved" AS (
+			UPDATE "flash_sales_products" SET
+				"sold_qty" = "sold_qty" + $1
+			WHERE "flash_sale_id" = $2
+			AND "product_id" = $3
+			RETURNING "flash_sale_id", "product_id"
+		)
+		INSERT INTO "flash_sales_orders" (
+			"flash_sale_id",
+			"product_id",
+			"order_id",
+			"user_id",
+			"qty"
+		)
+		SELECT
+			"r"."flash_sale_id",
+			"r"."product_id",
+			$4,
+			$5,
+			$1
+		FROM "reserved" "r";
+	`
+
+	for _, key := range keys {
+		flashSale := &struct {
+			Running         bool `db:"running"`
+			LeftQty         int  `db:"left_qty"`
+			CustomerLeftQty *int `db:"customer_left_qty"`
+		}{}
+		if err := b.tx.GetContext(ctx, flashSale, queryCheck, key.flashSaleId, key.productId, b.req.UserId); err != nil {
+			b.tx.Rollback()
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("flash sale of product %s has ended", key.productId)
+			}
+			return fmt.Errorf("failed to reserve flash sale: %w", err)
+		}
+
+		qty := qtyMap[key]
+		switch {
+		case !flashSale.Running:
+			b.tx.Rollback()
+			return fmt.Errorf("flash sale of product %s has ended", key.productId)
+		case flashSale.LeftQty < qty:
+			b.tx.Rollback()
+			return fmt.Errorf("flash sale of product %s has only %d left", key.productId, max(flashSale.LeftQty, 0))
+		case flashSale.CustomerLeftQty != nil && *flashSale.CustomerLeftQty < qty:
+			b.tx.Rollback()
+			return fmt.Errorf("flash sale of product %s allows %d more per customer", key.productId, max(*flashSale.CustomerLeftQty, 0))
+		}
+
+		if _, err := b.tx.ExecContext(ctx, queryReserve, qty, key.flashSaleId, key.productId, b.req.Id, b.req.UserId); err != nil {
+			b.tx.Rollback()
+			return fmt.Errorf("failed to reserve flash sale: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (b *insertOrderBuilder) commit() error {
 	if err := b.tx.Commit(); err != nil {
 		b.tx.Rollback()
@@ -119,10 +409,11 @@ func (b *insertOrderBuilder) getOrderId() string {
 	return b.req.Id
 }
 
-func InsertOrderBuilder(db *sqlx.DB, req *orders.Order) IInsertOrderBuilder {
+func InsertOrderBuilder(db *sqlx.DB, req *orders.Order, cfg config.IConfig) IInsertOrderBuilder {
 	return &insertOrderBuilder{
 		db:  db,
 		req: req,
+		cfg: cfg,
 	}
 }
 
@@ -137,6 +428,10 @@ func (en *insertOrderEngineer) InsertOrder() (string, error) {
 		return "", err
 	}
 
+	if err := en.builder.generateOrderNumber(); err != nil {
+		return "", err
+	}
+
 	if err := en.builder.insertOrder(); err != nil {
 		return "", err
 	}
@@ -145,6 +440,14 @@ func (en *insertOrderEngineer) InsertOrder() (string, error) {
 		return "", err
 	}
 
+	if err := en.builder.reserveStock(); err != nil {
+		return "", err
+	}
+
+	if err := en.builder.reserveFlashSale(); err != nil {
+		return "", err
+	}
+
 	if err := en.builder.commit(); err != nil {
 		return "", err
 	}
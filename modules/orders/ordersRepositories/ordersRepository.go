@@ -2,10 +2,14 @@ package ordersRepositories
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/IzePhanthakarn/go-basic-shop/config"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/orders"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersPatterns"
 	"github.com/jmoiron/sqlx"
@@ -14,17 +18,23 @@ import (
 type IOrdersRepository interface {
 	FindOneOrder(orderId string) (*orders.Order, error)
 	FindOrder(req *orders.OrderFilter) ([]*orders.Order, int)
+	FindOrderCursor(req *orders.OrderFilter) ([]*orders.Order, int)
 	InsertOrder(req *orders.Order) (string, error)
 	UpdateOrder(req *orders.Order) error
+	ExpireOrder(expires time.Duration) (int, error)
+	FindMessage(orderId string, isAdmin bool) ([]*orders.Message, error)
+	InsertMessage(req *orders.Message) error
 }
 
 type ordersRepository struct {
-	db *sqlx.DB
+	db  *sqlx.DB
+	cfg config.IConfig
 }
 
-func OrdersRepository(db *sqlx.DB) IOrdersRepository {
+func OrdersRepository(db *sqlx.DB, cfg config.IConfig) IOrdersRepository {
 	return &ordersRepository{
-		db: db,
+		db:  db,
+		cfg: cfg,
 	}
 }
 
@@ -35,6 +45,7 @@ func (r *ordersRepository) FindOneOrder(orderId string) (*orders.Order, error) {
 		FROM (
 			SELECT
 				"o"."id",
+				"o"."number",
 				"o"."user_id",
 				"o"."transfer_slip",
 				(
@@ -44,7 +55,8 @@ func (r *ordersRepository) FindOneOrder(orderId string) (*orders.Order, error) {
 						SELECT
 							"spo"."id",
 							"spo"."qty",
-							"spo"."product"
+							"spo"."product",
+							"spo"."variant"
 						FROM "products_orders" "spo"
 						WHERE "spo"."order_id" = "o"."id"
 					) AS "pt"
@@ -58,6 +70,29 @@ func (r *ordersRepository) FindOneOrder(orderId string) (*orders.Order, error) {
 					FROM "products_orders" "po"
 					WHERE "po"."order_id" = "o"."id"
 				) AS "total_paid",
+				(
+					SELECT
+						array_to_json(array_agg("tt"))
+					FROM (
+						SELECT
+							"ot"."id",
+							"ot"."event",
+							"ot"."message",
+							"ot"."created_at"
+						FROM "orders_timelines" "ot"
+						WHERE "ot"."order_id" = "o"."id"
+						UNION ALL
+						SELECT
+							"om"."id",
+							'message',
+							"om"."message",
+							"om"."created_at"
+						FROM "orders_messages" "om"
+						WHERE "om"."order_id" = "o"."id"
+						AND "om"."is_internal" = FALSE
+						ORDER BY "created_at" ASC
+					) AS "tt"
+				) AS "timeline",
 				"o"."created_at",
 				"o"."updated_at"
 			FROM "orders" "o"
@@ -91,8 +126,22 @@ func (r *ordersRepository) FindOrder(req *orders.OrderFilter) ([]*orders.Order,
 	return result, count
 }
 
+// FindOrderCursor counts only when the total is asked for.
+func (r *ordersRepository) FindOrderCursor(req *orders.OrderFilter) ([]*orders.Order, int) {
+	builder := ordersPatterns.FindOrderBuilder(r.db, req)
+	engineer := ordersPatterns.FindOrderEngineer(builder)
+
+	result := engineer.FindOrderCursor()
+	if !req.WithTotal {
+		return result, 0
+	}
+	count := engineer.CountOrder()
+
+	return result, count
+}
+
 func (r *ordersRepository) InsertOrder(req *orders.Order) (string, error) {
-	builder := ordersPatterns.InsertOrderBuilder(r.db, req)
+	builder := ordersPatterns.InsertOrderBuilder(r.db, req, r.cfg)
 	orderId, err := ordersPatterns.InsertOrderEngineer(builder).InsertOrder()
 	if err != nil {
 		return "", err
@@ -102,6 +151,39 @@ func (r *ordersRepository) InsertOrder(req *orders.Order) (string, error) {
 }
 
 func (r *ordersRepository) UpdateOrder(req *orders.Order) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Lock the order, so the stock can't be released twice, a customer
+	// (UserId) finds their own orders only
+	queryLock := `SELECT "status" FROM "orders" WHERE "id" = $1 FOR UPDATE;`
+	lockValues := []any{req.Id}
+	if req.UserId != "" {
+		queryLock = `SELECT "status" FROM "orders" WHERE "id" = $1 AND "user_id" = $2 FOR UPDATE;`
+		lockValues = append(lockValues, req.UserId)
+	}
+
+	var status string
+	if err := tx.GetContext(ctx, &status, queryLock, lockValues...); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("order not found")
+		}
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+	if status == "canceled" && req.Status != "" && req.Status != "canceled" {
+		tx.Rollback()
+		return fmt.Errorf("canceled order can not be changed")
+	}
+	if req.UserId != "" && req.Status == "canceled" && status != "waiting" {
+		tx.Rollback()
+		return fmt.Errorf("only a waiting order can be canceled")
+	}
+
 	query := `
 		UPDATE "orders" SET
 	`
@@ -151,10 +233,120 @@ func (r *ordersRepository) UpdateOrder(req *orders.Order) error {
 	}
 	query += queryClose
 
-	fmt.Println(query)
-	if _, err := r.db.ExecContext(context.Background(), query, values...); err != nil {
+	if _, err := tx.ExecContext(ctx, query, values...); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to update order: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// ExpireOrder cancels the orders that are still waiting after expires, a
+// transfer slip that hasn't been approved doesn't keep them. The timeline
+// event and the user notification are written in the same statement, the
+// stock and the flash sale quantity are given back by the canceled order
+// triggers.
+func (r *ordersRepository) ExpireOrder(expires time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	query := `
+	WITH "expired" AS (
+		UPDATE "orders" SET
+			"status" = 'canceled'
+		WHERE "status" = 'waiting'
+		AND "created_at" < now() - make_interval(secs => $1)
+		RETURNING "id", "number", "user_id"
+	), "timeline" AS (
+		INSERT INTO "orders_timelines" (
+			"order_id",
+			"event",
+			"message"
+		)
+		SELECT
+			"e"."id",
+			'expired',
+			'order was canceled because it was not paid in time'
+		FROM "expired" "e"
+	)
+	INSERT INTO "notifications" (
+		"user_id",
+		"title",
+		"message"
+	)
+	SELECT
+		"e"."user_id",
+		'Order canceled',
+		CONCAT('Your order ', "e"."number", ' was canceled because it was not paid in time')
+	FROM "expired" "e";`
+
+	result, err := r.db.ExecContext(ctx, query, expires.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire order: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	return int(rows), nil
+}
+
+func (r *ordersRepository) FindMessage(orderId string, isAdmin bool) ([]*orders.Message, error) {
+	query := `
+	SELECT
+		COALESCE(array_to_json(array_agg("t")), '[]'::json)
+	FROM (
+		SELECT
+			"om"."id",
+			"om"."order_id",
+			"om"."user_id",
+			"om"."message",
+			"om"."attachments",
+			"om"."is_internal",
+			"om"."created_at"
+		FROM "orders_messages" "om"
+		WHERE "om"."order_id" = $1
+		AND ("om"."is_internal" = FALSE OR $2)
+		ORDER BY "om"."created_at" ASC
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, orderId, isAdmin); err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	messages := make([]*orders.Message, 0)
+	if err := json.Unmarshal(raw, &messages); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal messages: %w", err)
+	}
+	return messages, nil
+}
+
+func (r *ordersRepository) InsertMessage(req *orders.Message) error {
+	query := `
+	INSERT INTO "orders_messages" (
+		"order_id",
+		"user_id",
+		"message",
+		"attachments",
+		"is_internal"
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING "id", "created_at";`
+
+	if err := r.db.QueryRowxContext(
+		context.Background(),
+		query,
+		req.OrderId,
+		req.UserId,
+		req.Message,
+		req.Attachments,
+		req.IsInternal,
+	).Scan(&req.Id, &req.CreatedAt); err != nil {
+		return fmt.Errorf("failed to insert message: %w", err)
+	}
 	return nil
 }
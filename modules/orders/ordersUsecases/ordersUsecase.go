@@ -2,19 +2,26 @@ package ordersUsecases
 
 import (
 	"fmt"
+	"log"
 	"math"
+	"time"
 
 	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/orders"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsRepositories"
 )
 
 type IOrdersUsecase interface {
 	FindOneOrder(orderId string) (*orders.Order, error)
 	FindOrder(req *orders.OrderFilter) *entities.PaginateRes
+	FindOrderCursor(req *orders.OrderFilter) *entities.CursorRes
 	InsertOrder(req *orders.Order) (*orders.Order, error)
 	UpdateOrder(req *orders.Order) (*orders.Order, error)
+	ExpireOrder(expires time.Duration) error
+	FindMessage(userId, orderId string, isAdmin bool) ([]*orders.Message, error)
+	InsertMessage(userId string, req *orders.Message) (*orders.Message, error)
 }
 
 type ordersUsecase struct {
@@ -48,6 +55,24 @@ func (u *ordersUsecase) FindOrder(req *orders.OrderFilter) *entities.PaginateRes
 	}
 }
 
+func (u *ordersUsecase) FindOrderCursor(req *orders.OrderFilter) *entities.CursorRes {
+	ordersData, count := u.ordersRepository.FindOrderCursor(req)
+
+	res := entities.NewCursorRes(req.CursorReq, req.OrderBy, req.SortBy, req.Limit, ordersData, func(o *orders.Order) (string, string) {
+		switch req.OrderBy {
+		case "number":
+			return o.Number, o.Id
+		case "created_at":
+			return o.CreatedAt, o.Id
+		}
+		return o.Id, o.Id
+	})
+	if req.WithTotal {
+		res.TotalItem = &count
+	}
+	return res
+}
+
 func (u *ordersUsecase) InsertOrder(req *orders.Order) (*orders.Order, error) {
 	// Check product is exist
 	for i := range req.Products {
@@ -55,13 +80,60 @@ func (u *ordersUsecase) InsertOrder(req *orders.Order) (*orders.Order, error) {
 			return nil, fmt.Errorf("product not nil")
 		}
 
+		if req.Products[i].Qty < 1 {
+			return nil, fmt.Errorf("qty must be greater than 0")
+		}
+
 		product, err := u.productsRepositories.FindOneProduct(req.Products[i].Product.Id)
 		if err != nil {
 			return nil, err
 		}
+		if !product.Published {
+			return nil, fmt.Errorf("product %s is not available", product.Id)
+		}
+
+		// Pick the variant, its price overrides the product price
+		if len(product.Variants) > 0 {
+			if req.Products[i].Variant == nil {
+				return nil, fmt.Errorf("variant of product %s is required", product.Id)
+			}
+
+			var variant *products.Variant
+			for _, v := range product.Variants {
+				if v.Id == req.Products[i].Variant.Id {
+					variant = v
+					break
+				}
+			}
+			if variant == nil {
+				return nil, fmt.Errorf("variant %s of product %s not found", req.Products[i].Variant.Id, product.Id)
+			}
+
+			if variant.Price != nil {
+				product.Price = *variant.Price
+			}
+			req.Products[i].Variant = variant
+		} else {
+			req.Products[i].Variant = nil
+		}
+
+		// The flash sale price wins over the variant price, the builder
+		// reserves the quantity when the order is placed
+		if product.FlashSale != nil {
+			product.Price = product.FlashSale.Price
+		}
+
+		// The order keeps only the bought variant
+		product.Options = nil
+		product.Variants = nil
+		product.Status = ""
+		product.DeletedAt = nil
+		product.PublishAt = nil
+		product.UnpublishAt = nil
+		product.PreviewToken = ""
 
 		// Set price
-		req.TotalPaid += req.Products[i].Product.Price * float64(req.Products[i].Qty)
+		req.TotalPaid += product.Price * float64(req.Products[i].Qty)
 		req.Products[i].Product = product
 	}
 
@@ -78,7 +150,14 @@ func (u *ordersUsecase) InsertOrder(req *orders.Order) (*orders.Order, error) {
 	return order, nil
 }
 
+// UpdateOrder changes the status or the transfer slip, a customer (UserId) can
+// only cancel their own waiting order, shipping and completing it is up to the
+// admins.
 func (u *ordersUsecase) UpdateOrder(req *orders.Order) (*orders.Order, error) {
+	if req.UserId != "" && req.Status != "" && req.Status != "canceled" {
+		return nil, fmt.Errorf("status %s can only be set by an admin", req.Status)
+	}
+
 	if err := u.ordersRepository.UpdateOrder(req); err != nil {
 		return nil, err
 	}
@@ -90,3 +169,51 @@ func (u *ordersUsecase) UpdateOrder(req *orders.Order) (*orders.Order, error) {
 
 	return order, nil
 }
+
+func (u *ordersUsecase) ExpireOrder(expires time.Duration) error {
+	count, err := u.ordersRepository.ExpireOrder(expires)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		log.Printf("%d waiting orders expired", count)
+	}
+	return nil
+}
+
+// checkOwner makes sure the order belongs to the user in the path, the same
+// user that ParamsCheck has already matched with the token.
+func (u *ordersUsecase) checkOwner(userId, orderId string) error {
+	order, err := u.ordersRepository.FindOneOrder(orderId)
+	if err != nil {
+		return err
+	}
+	if order.UserId != userId {
+		return fmt.Errorf("order not found")
+	}
+	return nil
+}
+
+func (u *ordersUsecase) FindMessage(userId, orderId string, isAdmin bool) ([]*orders.Message, error) {
+	if err := u.checkOwner(userId, orderId); err != nil {
+		return nil, err
+	}
+
+	messages, err := u.ordersRepository.FindMessage(orderId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (u *ordersUsecase) InsertMessage(userId string, req *orders.Message) (*orders.Message, error) {
+	if err := u.checkOwner(userId, req.OrderId); err != nil {
+		return nil, err
+	}
+
+	if err := u.ordersRepository.InsertMessage(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
@@ -1,24 +1,322 @@
 package products
 
 import (
+	"fmt"
+	"mime/multipart"
+	"strings"
+
 	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
 )
 
 type Product struct {
-	Id          string            `json:"id"`
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Category    *appinfo.Category `json:"category"`
-	CreatedAt   string            `json:"created_at"`
-	UpdatedAt   string            `json:"updated_at"`
-	Price       float64           `json:"price"`
-	Images      []*entities.Image `json:"images"`
+	Id             string                `json:"id"`
+	Sku            string                `json:"sku,omitempty"`
+	Title          string                `json:"title"`
+	Slug           string                `json:"slug"` // generated from the title when a new product has none, it stays when the title changes
+	Description    string                `json:"description"`
+	Category       *appinfo.Category     `json:"category"` // the main category
+	Categories     []*appinfo.Category   `json:"categories"`
+	CreatedAt      string                `json:"created_at"`
+	UpdatedAt      string                `json:"updated_at"`
+	Status         string                `json:"status,omitempty"`     // draft | active | archived
+	DeletedAt      *string               `json:"deleted_at,omitempty"` // when it was archived
+	PublishAt      *string               `json:"publish_at,omitempty"` // RFC3339, "" clears it
+	UnpublishAt    *string               `json:"unpublish_at,omitempty"`
+	Published      bool                  `json:"published"` // visible to customers right now
+	PreviewToken   string                `json:"preview_token,omitempty"`
+	Price          float64               `json:"price"`
+	CompareAtPrice *float64              `json:"compare_at_price,omitempty"` // the original price while a sale price is in effect
+	FlashSale      *FlashSale            `json:"flash_sale,omitempty"`       // the running flash sale, its price replaces the product and variant prices
+	InStock        bool                  `json:"in_stock"`
+	AvailableQty   *int                  `json:"available_qty"` // null when the stock isn't tracked
+	Rating         float64               `json:"rating"`        // the average of the approved reviews
+	ReviewCount    int                   `json:"review_count"`
+	Images         []*entities.Image     `json:"images"`
+	Options        []*Option             `json:"options,omitempty"`
+	Variants       []*Variant            `json:"variants,omitempty"`
+	Attributes     map[string]any        `json:"attributes,omitempty"` // values by attribute name, checked against the attributes of its categories
+	Questions      *entities.PaginateRes `json:"questions,omitempty"`  // a page of the answered questions, FindOneProduct only
+}
+
+type Option struct {
+	Id     string   `json:"id"`
+	Name   string   `json:"name"`   // e.g. size, color
+	Values []string `json:"values"` // e.g. 250g, 500g, 1kg
+}
+
+type Variant struct {
+	Id           string            `json:"id"`
+	Sku          string            `json:"sku"`
+	Options      map[string]string `json:"options"` // option name -> value
+	Price        *float64          `json:"price"`   // null uses the product price
+	InStock      bool              `json:"in_stock"`
+	AvailableQty int               `json:"available_qty"`
+	Image        *entities.Image   `json:"image"`
+}
+
+type FlashSale struct {
+	Id               string  `json:"id"`
+	Title            string  `json:"title"`
+	Price            float64 `json:"price"`
+	Qty              int     `json:"qty"` // the total quantity cap
+	SoldQty          int     `json:"sold_qty"`
+	PerCustomerLimit *int    `json:"per_customer_limit"` // null is unlimited
+	StartAt          string  `json:"start_at"`
+	EndAt            string  `json:"end_at"`
 }
 
 type ProductFilter struct {
-	Id     string `query:"id"`
-	Search string `query:"search"` // search by title and description
+	Id           string              `query:"id"`
+	Search       string              `query:"search"`      // search by title and description
+	CategoryId   []int               `query:"category_id"` // any of the categories, with their descendants
+	MinPrice     float64             `query:"min_price"`
+	MaxPrice     float64             `query:"max_price"`
+	InStock      bool                `query:"in_stock"`
+	MinRating    float64             `query:"min_rating"`
+	StartDate    string              `query:"start_date"` // date added
+	EndDate      string              `query:"end_date"`
+	Facets       bool                `query:"facets"`        // count products per category and price bucket
+	PrimaryImage bool                `query:"primary_image"` // images hold the primary image only
+	Status       string              `query:"status"`        // admins only, they see every product by default
+	IsAdmin      bool                `query:"-"`
+	Attributes   map[string][]string `query:"-"` // any of the values by attribute name, from attr.<name>
 	*entities.PaginationReq
 	*entities.SortReq
+	*entities.CursorReq
+}
+
+// ParseAttributes reads the attr.<name> queries into Attributes, a query can
+// hold several values separated by commas, e.g. attr.roast=dark,medium.
+func (f *ProductFilter) ParseAttributes(queries map[string]string) error {
+	for key, value := range queries {
+		name, ok := strings.CutPrefix(key, "attr.")
+		if !ok {
+			continue
+		}
+		if !appinfo.AttributeNameRegexp.MatchString(name) {
+			return fmt.Errorf("attribute %s is invalid", name)
+		}
+
+		values := make([]string, 0)
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		if f.Attributes == nil {
+			f.Attributes = make(map[string][]string)
+		}
+		f.Attributes[name] = values
+	}
+	return nil
+}
+
+// SortFields are the fields that sort accepts, relevance is ranked by the
+// builder from the search
+var SortFields = map[string]string{
+	"id":            `"p"."id"`,
+	"title":         `"p"."title"`,
+	"price":         `"p"."price"`,
+	"available_qty": `"p"."stock"`,
+	"rating":        `"p"."rating"`,
+	"review_count":  `"p"."review_count"`,
+	"created_at":    `"p"."created_at"`,
+	"updated_at":    `"p"."updated_at"`,
+	"relevance":     "",
+}
+
+// CursorOrderBy are the orders that the cursor mode supports, with the SQL type of their value
+var CursorOrderBy = map[string]string{
+	"id":         "VARCHAR",
+	"title":      "VARCHAR",
+	"price":      "FLOAT",
+	"rating":     "FLOAT",
+	"created_at": "TIMESTAMP",
+}
+
+// PriceBuckets are the lower bounds of the price facet, the last one has no upper bound
+var PriceBuckets = []float64{0, 100, 500, 1000, 5000}
+
+// PublishedQuery tells whether the product "p" is visible to customers, drafts
+// and archived products never are
+const PublishedQuery = `("p"."status" = 'active'
+	AND ("p"."publish_at" IS NULL OR "p"."publish_at" <= now())
+	AND ("p"."unpublish_at" IS NULL OR "p"."unpublish_at" > now()))`
+
+// FlashSaleQuery is the running flash sale of the product "p" that still has
+// quantity left, a product is in one flash sale at a time
+const FlashSaleQuery = `(
+	SELECT
+		to_jsonb("fst")
+	FROM (
+		SELECT
+			"f"."id",
+			"f"."title",
+			"fp"."price",
+			"fp"."qty",
+			"fp"."sold_qty",
+			"fp"."per_customer_limit",
+			"f"."start_at",
+			"f"."end_at"
+		FROM "flash_sales_products" "fp"
+			INNER JOIN "flash_sales" "f" ON "f"."id" = "fp"."flash_sale_id"
+		WHERE "fp"."product_id" = "p"."id"
+		AND "f"."start_at" <= now()
+		AND "f"."end_at" > now()
+		AND "fp"."sold_qty" < "fp"."qty"
+		LIMIT 1
+	) AS "fst"
+)`
+
+// Statuses are the statuses a product can have, archived products are hidden
+// from customers
+var Statuses = map[string]bool{
+	"draft":    true,
+	"active":   true,
+	"archived": true,
+}
+
+type ProductPaginateRes struct {
+	*entities.PaginateRes
+	Facets *Facets `json:"facets,omitempty"`
+}
+
+type ProductCursorRes struct {
+	*entities.CursorRes
+	Facets *Facets `json:"facets,omitempty"`
+}
+
+type Facets struct {
+	Categories []*CategoryFacet `json:"categories"`
+	Prices     []*PriceFacet    `json:"prices"`
+}
+
+// CategoryFacet counts the products of the category and its descendants
+type CategoryFacet struct {
+	Id       int    `json:"id"`
+	Title    string `json:"title"`
+	ParentId *int   `json:"parent_id"`
+	Count    int    `json:"count"`
+}
+
+type PriceFacet struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"` // null for the last bucket
+	Count int      `json:"count"`
+}
+
+// ImportColumns are the columns of the import and export CSV, categories and
+// images hold many values split by ImportSeparator, a category is written by
+// its slug and read by its slug or its title
+var ImportColumns = []string{"id", "sku", "title", "description", "price", "status", "categories", "images"}
+
+const (
+	ImportSeparator = "|"
+	ImportLimit     = 1000 // rows per file
+)
+
+type ImportReq struct {
+	File       *multipart.FileHeader // CSV
+	Images     *multipart.FileHeader // zip of the images the CSV names, optional
+	DryRun     bool
+	ImageLimit int // bytes per image
+}
+
+type ImportRes struct {
+	DryRun   bool            `json:"dry_run"`
+	Total    int             `json:"total"`
+	Inserted int             `json:"inserted"`
+	Updated  int             `json:"updated"`
+	Rows     []*ImportRowRes `json:"rows"`
+	Errors   []*ImportRowErr `json:"errors"`
+}
+
+type ImportRowRes struct {
+	Row    int    `json:"row"` // line of the CSV, the header is line 1
+	Id     string `json:"id,omitempty"`
+	Action string `json:"action"` // insert | update
+}
+
+type ImportRowErr struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Msg    string `json:"msg"`
+}
+
+// ProductKey is what an import row is matched by
+type ProductKey struct {
+	Id  string `db:"id"`
+	Sku string `db:"sku"`
+}
+
+type Price struct {
+	Id             string   `db:"id" json:"id"`
+	ProductId      string   `db:"product_id" json:"product_id"`
+	Price          float64  `db:"price" json:"price"`
+	CompareAtPrice *float64 `db:"compare_at_price" json:"compare_at_price"`
+	EffectiveFrom  string   `db:"effective_from" json:"effective_from"`
+	EffectiveTo    *string  `db:"effective_to" json:"effective_to"` // null until the next price
+	UserId         string   `db:"user_id" json:"user_id,omitempty"`
+	CreatedAt      string   `db:"created_at" json:"created_at"`
+}
+
+type PriceReq struct {
+	ProductId      string   `json:"-"`
+	UserId         string   `json:"-"`
+	Price          float64  `json:"price"`
+	CompareAtPrice *float64 `json:"compare_at_price"` // shown as the original price, e.g. for a sale
+	EffectiveFrom  string   `json:"effective_from"`   // RFC3339, empty is now
+	EffectiveTo    string   `json:"effective_to"`     // RFC3339, optional, the price in effect before comes back then
+}
+
+// SlugRes is what a slug points to, a moved slug is an old slug of the product
+type SlugRes struct {
+	Id    string `db:"id" json:"id"`
+	Slug  string `db:"slug" json:"slug"` // the current slug
+	Moved bool   `db:"moved" json:"-"`
+}
+
+type PreviewTokenRes struct {
+	PreviewToken string `json:"preview_token"`
+}
+
+// ImageReq adds or edits one image of a product, the first image is the
+// primary one
+type ImageReq struct {
+	ProductId string  `json:"-"`
+	ImageId   string  `json:"-"`
+	Filename  string  `json:"filename"` // adding only
+	Url       string  `json:"url"`      // adding only
+	Alt       *string `json:"alt"`
+	IsPrimary bool    `json:"is_primary"` // moves the image first
+}
+
+type ImageOrderReq struct {
+	ImageIds []string `json:"image_ids"` // every image of the product, the first one is primary
+}
+
+type CategoryReq struct {
+	CategoryId int `json:"category_id"`
+}
+
+type StockAdjustment struct {
+	Id        string `db:"id" json:"id"`
+	ProductId string `db:"product_id" json:"product_id"`
+	VariantId string `db:"variant_id" json:"variant_id,omitempty"`
+	Qty       int    `db:"qty" json:"qty"` // + stock in, - stock out
+	Reason    string `db:"reason" json:"reason"`
+	OrderId   string `db:"order_id" json:"order_id,omitempty"`
+	UserId    string `db:"user_id" json:"user_id,omitempty"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+}
+
+type StockAdjustmentReq struct {
+	VariantId string `json:"variant_id"`
+	Qty       int    `json:"qty"`
+	Reason    string `json:"reason"`
 }
@@ -1,16 +1,26 @@
 package productsHandlers
 
 import (
+	"bytes"
+	"crypto/subtle"
 	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/IzePhanthakarn/go-basic-shop/config"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/files"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/files/filesUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/middlewares"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions/questionsUsecases"
 	"github.com/gofiber/fiber/v3"
 )
 
@@ -22,27 +32,59 @@ const (
 	insertProductErr  productsHanflersErrCode = "products-003"
 	updateProductErr  productsHanflersErrCode = "products-004"
 	deleteProductErr  productsHanflersErrCode = "products-005"
+	findStockErr      productsHanflersErrCode = "products-006"
+	adjustStockErr    productsHanflersErrCode = "products-007"
+	addCategoryErr    productsHanflersErrCode = "products-008"
+	removeCategoryErr productsHanflersErrCode = "products-009"
+	restoreProductErr productsHanflersErrCode = "products-010"
+	previewTokenErr   productsHanflersErrCode = "products-011"
+	importProductErr  productsHanflersErrCode = "products-012"
+	exportProductErr  productsHanflersErrCode = "products-013"
+	findPriceErr      productsHanflersErrCode = "products-014"
+	schedulePriceErr  productsHanflersErrCode = "products-015"
+	addImageErr       productsHanflersErrCode = "products-016"
+	updateImageErr    productsHanflersErrCode = "products-017"
+	reorderImageErr   productsHanflersErrCode = "products-018"
+	removeImageErr    productsHanflersErrCode = "products-019"
 )
 
 type IProductsHandler interface {
 	FindOneProduct(c fiber.Ctx) error
+	FindProductBySlug(c fiber.Ctx) error
 	FindProduct(c fiber.Ctx) error
 	AddProduct(c fiber.Ctx) error
 	UpdateProduct(c fiber.Ctx) error
 	DeleteProduct(c fiber.Ctx) error
+	RestoreProduct(c fiber.Ctx) error
+	RotatePreviewToken(c fiber.Ctx) error
+	RevokePreviewToken(c fiber.Ctx) error
+	ImportProduct(c fiber.Ctx) error
+	ExportProduct(c fiber.Ctx) error
+	FindPrice(c fiber.Ctx) error
+	SchedulePrice(c fiber.Ctx) error
+	AddCategory(c fiber.Ctx) error
+	RemoveCategory(c fiber.Ctx) error
+	AddImage(c fiber.Ctx) error
+	UpdateImage(c fiber.Ctx) error
+	ReorderImage(c fiber.Ctx) error
+	RemoveImage(c fiber.Ctx) error
+	FindStock(c fiber.Ctx) error
+	AdjustStock(c fiber.Ctx) error
 }
 
 type productsHandler struct {
-	cfg             config.IConfig
-	filesUsecase    filesUsecases.IFilesUsecase
-	productsUsecase productsUsecases.IProductsUsecase
+	cfg              config.IConfig
+	filesUsecase     filesUsecases.IFilesUsecase
+	productsUsecase  productsUsecases.IProductsUsecase
+	questionsUsecase questionsUsecases.IQuestionsUsecase
 }
 
-func ProductsHandler(cfg config.IConfig, filesUsecase filesUsecases.IFilesUsecase, productsUsecase productsUsecases.IProductsUsecase) IProductsHandler {
+func ProductsHandler(cfg config.IConfig, filesUsecase filesUsecases.IFilesUsecase, productsUsecase productsUsecases.IProductsUsecase, questionsUsecase questionsUsecases.IQuestionsUsecase) IProductsHandler {
 	return &productsHandler{
-		cfg:             cfg,
-		filesUsecase:    filesUsecase,
-		productsUsecase: productsUsecase,
+		cfg:              cfg,
+		filesUsecase:     filesUsecase,
+		productsUsecase:  productsUsecase,
+		questionsUsecase: questionsUsecase,
 	}
 }
 
@@ -52,12 +94,85 @@ func ProductsHandler(cfg config.IConfig, filesUsecase filesUsecases.IFilesUsecas
 // @Accept  json
 // @Produce  json
 // @Param product_id path string true "Product ID"
+// @Param preview_token query string false "Preview Token, shows the product before it is published"
+// @Param questions_page query int false "Page of the answered questions" default(1)
+// @Param questions_limit query int false "Limit of the answered questions" default(5)
+// @Security BearerAuth
 // @Success 200 {object} products.Product
 // @Router /products/{product_id} [get]
 func (h *productsHandler) FindOneProduct(c fiber.Ctx) error {
-	productId := strings.Trim(c.Params("product_id"), " ")
+	return h.findOneProduct(c, strings.Trim(c.Params("product_id"), " "))
+}
+
+// @Summary Find One Product By Slug
+// @Description Find One Product by its slug, an old slug of a product the request can see responds 301 with the current slug and the same query in Location
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param slug path string true "Slug"
+// @Param preview_token query string false "Preview Token, shows the product before it is published"
+// @Param questions_page query int false "Page of the answered questions" default(1)
+// @Param questions_limit query int false "Limit of the answered questions" default(5)
+// @Security BearerAuth
+// @Success 200 {object} products.Product
+// @Success 301 {object} products.SlugRes
+// @Router /products/by-slug/{slug} [get]
+func (h *productsHandler) FindProductBySlug(c fiber.Ctx) error {
+	res, err := h.productsUsecase.FindProductSlug(strings.Trim(c.Params("slug"), " "))
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findOneProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	if !res.Moved {
+		return h.findOneProduct(c, res.Id)
+	}
+
+	// The current slug is only told to those who can see the product, the
+	// query like preview_token goes along
+	if _, err := h.visibleProduct(c, res.Id); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findOneProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	location := path.Dir(c.Path()) + "/" + url.PathEscape(res.Slug)
+	if query := string(c.Request().URI().QueryString()); query != "" {
+		location += "?" + query
+	}
+	c.Set(fiber.HeaderLocation, location)
+	return entities.NewResponse(c).Success(fiber.StatusMovedPermanently, res).Res()
+}
 
+// visibleProduct finds the product when the request can see it, customers
+// see it once it is published or with its preview token.
+func (h *productsHandler) visibleProduct(c fiber.Ctx, productId string) (*products.Product, error) {
 	product, err := h.productsUsecase.FindOneProduct(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !middlewares.IsAdmin(c) {
+		// A draft can be shared with its preview token before it is published
+		previewToken := c.Query("preview_token")
+		preview := previewToken != "" && product.Status != "archived" &&
+			subtle.ConstantTimeCompare([]byte(previewToken), []byte(product.PreviewToken)) == 1
+
+		if !product.Published && !preview {
+			return nil, fmt.Errorf("product not found")
+		}
+		product.PreviewToken = ""
+	}
+	return product, nil
+}
+
+func (h *productsHandler) findOneProduct(c fiber.Ctx, productId string) error {
+	product, err := h.visibleProduct(c, productId)
 	if err != nil {
 		return entities.NewResponse(c).Error(
 			fiber.StatusBadRequest,
@@ -66,6 +181,30 @@ func (h *productsHandler) FindOneProduct(c fiber.Ctx) error {
 		).Res()
 	}
 
+	// The answered questions come with the product, a page at a time
+	questionsReq := &questions.QuestionFilter{
+		ProductId: product.Id,
+		PaginationReq: &entities.PaginationReq{
+			Page:  fiber.Query[int](c, "questions_page", 1),
+			Limit: fiber.Query[int](c, "questions_limit", 5),
+		},
+	}
+	if questionsReq.Page < 1 {
+		questionsReq.Page = 1
+	}
+	if questionsReq.Limit < 1 || questionsReq.Limit > 100 {
+		questionsReq.Limit = 5
+	}
+
+	product.Questions, err = h.questionsUsecase.FindQuestion(questionsReq)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findOneProductErr),
+			err.Error(),
+		).Res()
+	}
+
 	return entities.NewResponse(c).Success(fiber.StatusOK, product).Res()
 }
 
@@ -77,15 +216,33 @@ func (h *productsHandler) FindOneProduct(c fiber.Ctx) error {
 // @Param id query string false "Id"
 // @Param page query int false "Page" default(1)
 // @Param limit query int false "Limit" default(10)
-// @Param order_by query string false "Order By field" default(id)
-// @Param sort_by query string false "Sort By direction (asc or desc)" default(desc)
-// @Param search query string false "Search by title | description"
-// @Success 200 {object} entities.PaginateRes
+// @Param sort query string false "Sort fields, e.g. price:asc,created_at:desc, fields are id | title | price | available_qty | rating | review_count | created_at | updated_at | relevance, relevance:desc is the default when searching" default(title:asc)
+// @Param order_by query string false "Order By field, used when sort is not sent"
+// @Param sort_by query string false "Sort By direction (asc or desc), used when sort is not sent"
+// @Param search query string false "Search by title | description, partial words are matched"
+// @Param category_id query []int false "Category IDs, any of them" collectionFormat(multi)
+// @Param min_price query number false "Min Price"
+// @Param max_price query number false "Max Price"
+// @Param in_stock query bool false "In stock only"
+// @Param min_rating query number false "Min average rating (1-5)"
+// @Param attr.roast query string false "Attribute filter, attr.<name> with any of the comma separated values, e.g. attr.roast=dark,medium"
+// @Param start_date query string false "Added from (YYYY-MM-DD)"
+// @Param end_date query string false "Added to (YYYY-MM-DD)"
+// @Param facets query bool false "Count products per category and price bucket"
+// @Param primary_image query bool false "Images hold the primary image only"
+// @Param status query string false "Status, admins only (draft | active | archived)"
+// @Param cursor query bool false "Cursor pagination instead of page, responds products.ProductCursorRes, order_by is id | title | price | rating | created_at"
+// @Param after query string false "Next page cursor"
+// @Param before query string false "Previous page cursor"
+// @Param with_total query bool false "Count total items in cursor mode"
+// @Security BearerAuth
+// @Success 200 {object} products.ProductPaginateRes
 // @Router /products [get]
 func (h *productsHandler) FindProduct(c fiber.Ctx) error {
 	req := &products.ProductFilter{
 		PaginationReq: &entities.PaginationReq{},
 		SortReq:       &entities.SortReq{},
+		CursorReq:     &entities.CursorReq{},
 	}
 
 	if err := c.Bind().Query(req); err != nil {
@@ -96,7 +253,27 @@ func (h *productsHandler) FindProduct(c fiber.Ctx) error {
 		).Res()
 	}
 
-	if req.Page < 0 {
+	if err := req.ParseAttributes(c.Queries()); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	req.IsAdmin = middlewares.IsAdmin(c)
+	if !req.IsAdmin {
+		req.Status = ""
+	}
+	if req.Status != "" && !products.Statuses[req.Status] {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findProductErr),
+			"status is invalid",
+		).Res()
+	}
+
+	if req.Page < 1 {
 		req.Page = 1
 	}
 
@@ -104,17 +281,92 @@ func (h *productsHandler) FindProduct(c fiber.Ctx) error {
 		req.Limit = 5
 	}
 
-	if req.OrderBy == "" {
-		req.OrderBy = "title"
+	if req.MinPrice < 0 || req.MaxPrice < 0 || (req.MaxPrice > 0 && req.MinPrice > req.MaxPrice) {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findProductErr),
+			"invalid price range",
+		).Res()
 	}
 
-	if req.SortBy == "" {
+	if req.MinRating < 0 || req.MinRating > 5 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findProductErr),
+			"min rating must be between 0 and 5",
+		).Res()
+	}
+
+	for _, date := range []*string{&req.StartDate, &req.EndDate} {
+		if *date == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", *date)
+		if err != nil {
+			return entities.NewResponse(c).Error(
+				fiber.StatusBadRequest,
+				string(findProductErr),
+				"invalid date",
+			).Res()
+		}
+		*date = parsed.Format("2006-01-02")
+	}
+
+	if req.IsCursor() {
+		// Cursor mode sorts by a single field
+		if err := req.SortReq.Parse(products.CursorOrderBy, "title:asc"); err != nil || len(req.Fields) != 1 {
+			return entities.NewResponse(c).Error(
+				fiber.StatusBadRequest,
+				string(findProductErr),
+				"sort is not supported with cursor",
+			).Res()
+		}
+
+		req.OrderBy = req.Fields[0].Field
 		req.SortBy = "ASC"
+		if req.Fields[0].Desc {
+			req.SortBy = "DESC"
+		}
+
+		if err := req.CursorReq.Decode(req.OrderBy, req.SortBy); err != nil {
+			return entities.NewResponse(c).Error(
+				fiber.StatusBadRequest,
+				string(findProductErr),
+				err.Error(),
+			).Res()
+		}
+
+		res := &products.ProductCursorRes{
+			CursorRes: h.productsUsecase.FindProductCursor(req),
+		}
+		if req.Facets {
+			res.Facets = h.productsUsecase.FindFacet(req)
+		}
+
+		return entities.NewResponse(c).Success(fiber.StatusOK, res).Res()
 	}
 
-	products := h.productsUsecase.FindProduct(req)
+	// Most relevant first when searching
+	defaultSort := "title:asc"
+	if req.Search != "" {
+		defaultSort = "relevance:desc"
+	}
+	if err := req.SortReq.Parse(products.SortFields, defaultSort); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findProductErr),
+			err.Error(),
+		).Res()
+	}
 
-	return entities.NewResponse(c).Success(fiber.StatusOK, products).Res()
+	res := &products.ProductPaginateRes{
+		PaginateRes: h.productsUsecase.FindProduct(req),
+	}
+	if req.Facets {
+		res.Facets = h.productsUsecase.FindFacet(req)
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, res).Res()
 }
 
 // @Summary Add Product
@@ -140,7 +392,7 @@ func (h *productsHandler) AddProduct(c fiber.Ctx) error {
 		).Res()
 	}
 
-	if req.Category.Id <= 0 {
+	if (req.Category == nil || req.Category.Id <= 0) && len(req.Categories) == 0 {
 		return entities.NewResponse(c).Error(
 			fiber.StatusBadRequest,
 			string(insertProductErr),
@@ -148,6 +400,14 @@ func (h *productsHandler) AddProduct(c fiber.Ctx) error {
 		).Res()
 	}
 
+	if req.AvailableQty != nil && *req.AvailableQty < 0 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertProductErr),
+			"available qty must not be negative",
+		).Res()
+	}
+
 	product, err := h.productsUsecase.AddProduct(req)
 	if err != nil {
 		return entities.NewResponse(c).Error(
@@ -199,17 +459,29 @@ func (h *productsHandler) UpdateProduct(c fiber.Ctx) error {
 }
 
 // @Summary Delete Product
-// @Description Delete Product
+// @Description Archive Product, purge deletes it with its images for good
 // @Tags Products
 // @Accept  json
 // @Produce  json
 // @Security BearerAuth
-// @Param product_id query string false "Product ID"
+// @Param product_id path string true "Product ID"
+// @Param purge query bool false "Delete the product and its images for good"
 // @Success 200 {array} nil
 // @Router /products/{product_id} [delete]
 func (h *productsHandler) DeleteProduct(c fiber.Ctx) error {
 	productId := strings.Trim(c.Params("product_id"), " ")
 
+	if c.Query("purge") != "true" {
+		if err := h.productsUsecase.DeleteProduct(productId); err != nil {
+			return entities.NewResponse(c).Error(
+				fiber.StatusBadRequest,
+				string(deleteProductErr),
+				err.Error(),
+			).Res()
+		}
+		return entities.NewResponse(c).Success(fiber.StatusOK, nil).Res()
+	}
+
 	product, err := h.productsUsecase.FindOneProduct(productId)
 	if err != nil {
 		return entities.NewResponse(c).Error(
@@ -234,7 +506,7 @@ func (h *productsHandler) DeleteProduct(c fiber.Ctx) error {
 		).Res()
 	}
 
-	if err := h.productsUsecase.DeleteProduct(productId); err != nil {
+	if err := h.productsUsecase.PurgeProduct(productId); err != nil {
 		return entities.NewResponse(c).Error(
 			fiber.StatusInternalServerError,
 			string(deleteProductErr),
@@ -244,3 +516,527 @@ func (h *productsHandler) DeleteProduct(c fiber.Ctx) error {
 
 	return entities.NewResponse(c).Success(fiber.StatusOK, nil).Res()
 }
+
+// @Summary Restore Product
+// @Description Bring an archived Product back
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Success 200 {object} products.Product
+// @Router /products/{product_id}/restore [post]
+func (h *productsHandler) RestoreProduct(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+
+	product, err := h.productsUsecase.RestoreProduct(productId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(restoreProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, product).Res()
+}
+
+// @Summary Add Product Category
+// @Description Add a category to a product
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Param request body products.CategoryReq true "Category Request"
+// @Success 201 {object} products.Product
+// @Router /products/{product_id}/categories [post]
+func (h *productsHandler) AddCategory(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+
+	req := new(products.CategoryReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(addCategoryErr),
+			err.Error(),
+		).Res()
+	}
+
+	if req.CategoryId <= 0 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(addCategoryErr),
+			"category id is required",
+		).Res()
+	}
+
+	product, err := h.productsUsecase.AddCategory(productId, req.CategoryId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(addCategoryErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, product).Res()
+}
+
+// @Summary Remove Product Category
+// @Description Remove a category from a product, the last category can't be removed
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Param category_id path int true "Category ID"
+// @Success 200 {object} products.Product
+// @Router /products/{product_id}/categories/{category_id} [delete]
+func (h *productsHandler) RemoveCategory(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+
+	categoryId, err := strconv.Atoi(strings.Trim(c.Params("category_id"), " "))
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(removeCategoryErr),
+			"id type is invalid",
+		).Res()
+	}
+
+	product, err := h.productsUsecase.RemoveCategory(productId, categoryId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(removeCategoryErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, product).Res()
+}
+
+// @Summary Add Product Image
+// @Description Add an uploaded image to a product, after the other images or first when it is primary
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Param request body products.ImageReq true "Image Request"
+// @Success 201 {object} products.Product
+// @Router /products/{product_id}/images [post]
+func (h *productsHandler) AddImage(c fiber.Ctx) error {
+	req := new(products.ImageReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(addImageErr),
+			err.Error(),
+		).Res()
+	}
+	req.ProductId = strings.Trim(c.Params("product_id"), " ")
+
+	product, err := h.productsUsecase.AddImage(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(addImageErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, product).Res()
+}
+
+// @Summary Update Product Image
+// @Description Set the alt text of an image or make it the primary image, filename and url are ignored
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Param image_id path string true "Image ID"
+// @Param request body products.ImageReq true "Image Request"
+// @Success 200 {object} products.Product
+// @Router /products/{product_id}/images/{image_id} [patch]
+func (h *productsHandler) UpdateImage(c fiber.Ctx) error {
+	req := new(products.ImageReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateImageErr),
+			err.Error(),
+		).Res()
+	}
+	req.ProductId = strings.Trim(c.Params("product_id"), " ")
+	req.ImageId = strings.Trim(c.Params("image_id"), " ")
+
+	product, err := h.productsUsecase.UpdateImage(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(updateImageErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, product).Res()
+}
+
+// @Summary Reorder Product Images
+// @Description Order the images of a product, the first one becomes the primary image
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Param request body products.ImageOrderReq true "Image Order Request"
+// @Success 200 {object} products.Product
+// @Router /products/{product_id}/images [put]
+func (h *productsHandler) ReorderImage(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+
+	req := new(products.ImageOrderReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(reorderImageErr),
+			err.Error(),
+		).Res()
+	}
+
+	product, err := h.productsUsecase.ReorderImage(productId, req.ImageIds)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(reorderImageErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, product).Res()
+}
+
+// @Summary Remove Product Image
+// @Description Remove an image from a product and delete its file, the next image becomes primary when it was
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} products.Product
+// @Router /products/{product_id}/images/{image_id} [delete]
+func (h *productsHandler) RemoveImage(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+	imageId := strings.Trim(c.Params("image_id"), " ")
+
+	product, err := h.productsUsecase.RemoveImage(productId, imageId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(removeImageErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, product).Res()
+}
+
+// @Summary Find Stock
+// @Description Find stock movements of a product
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Success 200 {array} products.StockAdjustment
+// @Router /products/{product_id}/stocks [get]
+func (h *productsHandler) FindStock(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+
+	stocks, err := h.productsUsecase.FindStock(productId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findStockErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, stocks).Res()
+}
+
+// @Summary Adjust Stock
+// @Description Adjust stock of a product, positive qty for stock in and negative qty for stock out
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Param request body products.StockAdjustmentReq true "Stock Adjustment Request"
+// @Success 201 {object} products.StockAdjustment
+// @Router /products/{product_id}/stocks [post]
+func (h *productsHandler) AdjustStock(c fiber.Ctx) error {
+	req := new(products.StockAdjustmentReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(adjustStockErr),
+			err.Error(),
+		).Res()
+	}
+
+	stock := &products.StockAdjustment{
+		ProductId: strings.Trim(c.Params("product_id"), " "),
+		VariantId: strings.TrimSpace(req.VariantId),
+		Qty:       req.Qty,
+		Reason:    strings.TrimSpace(req.Reason),
+		UserId:    c.Locals("userId").(string),
+	}
+
+	if err := h.productsUsecase.AdjustStock(stock); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(adjustStockErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, stock).Res()
+}
+
+// @Summary Rotate Preview Token
+// @Description Create a new Preview Token for the Product, the old one stops working
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Success 201 {object} products.PreviewTokenRes
+// @Router /products/{product_id}/preview-token [post]
+func (h *productsHandler) RotatePreviewToken(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+
+	res, err := h.productsUsecase.RotatePreviewToken(productId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(previewTokenErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, res).Res()
+}
+
+// @Summary Revoke Preview Token
+// @Description Revoke the Preview Token of the Product
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Success 200 {array} nil
+// @Router /products/{product_id}/preview-token [delete]
+func (h *productsHandler) RevokePreviewToken(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+
+	if err := h.productsUsecase.RevokePreviewToken(productId); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(previewTokenErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, nil).Res()
+}
+
+// @Summary Import Products
+// @Description Insert or update Products from a CSV with the columns id, sku, title, description, price, status, categories and images. Categories and images hold many values split by |, a category is a slug or a title that only one category has and an image is a URL or a file of the zip. A row with an id, or with the sku of a product, updates it, empty cells keep their value. Nothing is written when a row is invalid, the report responds with 422.
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce  json
+// @Security BearerAuth
+// @Param file formData file true "CSV file"
+// @Param images formData file false "Zip of the images"
+// @Param dry_run formData bool false "Validate only"
+// @Success 201 {object} products.ImportRes
+// @Router /products/import [post]
+func (h *productsHandler) ImportProduct(c fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(importProductErr),
+			"csv file is required",
+		).Res()
+	}
+
+	req := &products.ImportReq{
+		File:       file,
+		DryRun:     c.FormValue("dry_run") == "true",
+		ImageLimit: h.cfg.App().FileLimit(),
+	}
+	if images, err := c.FormFile("images"); err == nil {
+		if !strings.EqualFold(filepath.Ext(images.Filename), ".zip") {
+			return entities.NewResponse(c).Error(
+				fiber.StatusBadRequest,
+				string(importProductErr),
+				"images must be a zip file",
+			).Res()
+		}
+		req.Images = images
+	}
+
+	res, err := h.productsUsecase.ImportProduct(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(importProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	status := fiber.StatusCreated
+	if len(res.Errors) > 0 {
+		status = fiber.StatusUnprocessableEntity
+	} else if res.DryRun {
+		status = fiber.StatusOK
+	}
+	return entities.NewResponse(c).Success(status, res).Res()
+}
+
+// @Summary Export Products
+// @Description Export Products as a CSV in the import format
+// @Tags Products
+// @Produce  text/csv
+// @Security BearerAuth
+// @Param sort query string false "Sort fields, e.g. price:asc,created_at:desc" default(id:asc)
+// @Param search query string false "Search by title | description"
+// @Param category_id query []int false "Category IDs, any of them" collectionFormat(multi)
+// @Param min_price query number false "Min Price"
+// @Param max_price query number false "Max Price"
+// @Param in_stock query bool false "In stock only"
+// @Param status query string false "Status (draft | active | archived)"
+// @Param attr.roast query string false "Attribute filter, attr.<name> with any of the comma separated values"
+// @Success 200 {file} file
+// @Router /products/export [get]
+func (h *productsHandler) ExportProduct(c fiber.Ctx) error {
+	req := &products.ProductFilter{
+		PaginationReq: &entities.PaginationReq{},
+		SortReq:       &entities.SortReq{},
+		CursorReq:     &entities.CursorReq{},
+	}
+
+	if err := c.Bind().Query(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(exportProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	if err := req.ParseAttributes(c.Queries()); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(exportProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	if req.Status != "" && !products.Statuses[req.Status] {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(exportProductErr),
+			"status is invalid",
+		).Res()
+	}
+
+	if err := req.SortReq.Parse(products.SortFields, "id:asc"); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(exportProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	req.IsAdmin = true
+	req.Limit = 500
+	req.Cursor = false
+	req.Facets = false
+
+	buf := new(bytes.Buffer)
+	if err := h.productsUsecase.ExportProduct(req, buf); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(exportProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="products.csv"`)
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+// @Summary Find Prices
+// @Description Price history and scheduled prices of the Product, latest first
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Success 200 {array} products.Price
+// @Router /products/{product_id}/prices [get]
+func (h *productsHandler) FindPrice(c fiber.Ctx) error {
+	productId := strings.Trim(c.Params("product_id"), " ")
+
+	prices, err := h.productsUsecase.FindPrice(productId)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findPriceErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, prices).Res()
+}
+
+// @Summary Schedule Price
+// @Description Change the price of the Product now or later, compare_at_price is shown as the original price while the price is in effect
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param product_id path string true "Product ID"
+// @Param request body products.PriceReq true "Price Request"
+// @Success 201 {array} products.Price
+// @Router /products/{product_id}/prices [post]
+func (h *productsHandler) SchedulePrice(c fiber.Ctx) error {
+	req := new(products.PriceReq)
+	if err := c.Bind().Body(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(schedulePriceErr),
+			err.Error(),
+		).Res()
+	}
+	req.ProductId = strings.Trim(c.Params("product_id"), " ")
+	req.UserId = c.Locals("userId").(string)
+
+	prices, err := h.productsUsecase.SchedulePrice(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(schedulePriceErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, prices).Res()
+}
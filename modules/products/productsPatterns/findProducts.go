@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"strconv"
+	"maps"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
 	"github.com/IzePhanthakarn/go-basic-shop/pkg/utils"
@@ -21,10 +23,13 @@ type IFindProductBuilder interface {
 	whereQuery()
 	sort()
 	paginate()
+	cursor()
 	closeJsonQuery()
+	facetQuery()
 	resetQuery()
 	Result() []*products.Product
 	Count() int
+	Facets() *products.Facets
 	PrintQuery()
 }
 
@@ -33,6 +38,7 @@ type findProductBuilder struct {
 	req            *products.ProductFilter
 	query          string
 	lastStackIndex int
+	searchIndex    int
 	values         []any
 }
 
@@ -52,26 +58,76 @@ func (b *findProductBuilder) openJsonQuert() {
 }
 
 func (b *findProductBuilder) initQuery() {
+	// Lists can ask for the primary image only, it is the first one
+	var primaryImage string
+	if b.req.PrimaryImage {
+		primaryImage = `
+					LIMIT 1`
+	}
+
 	b.query += `
 		SELECT
 			"p"."id",
+			"p"."sku",
 			"p"."title",
+			"p"."slug",
 			"p"."description",
+			"p"."attributes",
 			"p"."price",
+			(
+				SELECT
+					CASE WHEN "pp"."compare_at_price" > "p"."price" THEN "pp"."compare_at_price" END
+				FROM "products_prices" "pp"
+				WHERE "pp"."product_id" = "p"."id"
+				AND "pp"."effective_from" <= now()
+				ORDER BY "pp"."effective_from" DESC
+				LIMIT 1
+			) AS "compare_at_price",
+			` + products.FlashSaleQuery + ` AS "flash_sale",
+			"p"."stock" AS "available_qty",
+			COALESCE("p"."stock" > 0, TRUE) AS "in_stock",
+			"p"."rating",
+			"p"."review_count",
 			(
 				SELECT
 					to_jsonb("ct")
 				FROM (
 					SELECT
 						"c"."id",
-						"c"."title"
+						"c"."title",
+						"c"."parent_id",
+						"cb"."breadcrumbs"
 					FROM "categories" "c"
 						LEFT JOIN "products_categories" "pc" ON "pc"."category_id" = "c"."id"
+						LEFT JOIN "categories_breadcrumbs" "cb" ON "cb"."category_id" = "c"."id"
 					WHERE "pc"."product_id" = "p"."id"
+					ORDER BY "pc"."created_at", "pc"."id"
+					LIMIT 1
 				) AS "ct"
 			) AS "category",
+			(
+				SELECT
+					COALESCE(array_to_json(array_agg("cst")), '[]'::json)
+				FROM (
+					SELECT
+						"c"."id",
+						"c"."title",
+						"c"."parent_id",
+						"cb"."breadcrumbs"
+					FROM "categories" "c"
+						INNER JOIN "products_categories" "pc" ON "pc"."category_id" = "c"."id"
+						LEFT JOIN "categories_breadcrumbs" "cb" ON "cb"."category_id" = "c"."id"
+					WHERE "pc"."product_id" = "p"."id"
+					ORDER BY "pc"."created_at", "pc"."id"
+				) AS "cst"
+			) AS "categories",
 			"p"."created_at",
 			"p"."updated_at",
+			"p"."status",
+			"p"."deleted_at",
+			"p"."publish_at",
+			"p"."unpublish_at",
+			` + products.PublishedQuery + ` AS "published",
 			(
 				SELECT
 					COALESCE(array_to_json(array_agg("it")), '[]'::json)
@@ -79,9 +135,11 @@ func (b *findProductBuilder) initQuery() {
 					SELECT
 						"i"."id",
 						"i"."filename",
-						"i"."url"
+						"i"."url",
+						"i"."alt"
 					FROM "images" "i"
 					WHERE "i"."product_id" = "p"."id"
+					ORDER BY "i"."position", "i"."created_at"` + primaryImage + `
 				) AS "it"
 			) AS "images"
 		FROM "products" "p"
@@ -99,73 +157,126 @@ func (b *findProductBuilder) countQuery() {
 }
 
 func (b *findProductBuilder) whereQuery() {
+	b.query += b.whereStack("")
+
+	// Update lastStackIndex
+	b.lastStackIndex = len(b.values)
+}
+
+// whereStack builds the filter of the request, a facet leaves its own filter
+// out (except), so its counts show what the other choices would give.
+func (b *findProductBuilder) whereStack(except string) string {
 	var queryWhere string
-	queryWhereStack := make([]string, 0)
 
 	// Id check
 	if b.req.Id != "" {
-		b.values = append(
-			b.values,
-			"%"+strings.ToLower(b.req.Search)+"%",
-			"%"+strings.ToLower(b.req.Search)+"%",
-		)
-		queryWhereStack = append(queryWhereStack, `
-			AND "p"."id" = ?`)
+		b.values = append(b.values, b.req.Id)
+		queryWhere += fmt.Sprintf(`
+			AND "p"."id" = $%d`, len(b.values))
+	}
+
+	// Status check, customers see published products only
+	if b.req.Status != "" {
+		b.values = append(b.values, b.req.Status)
+		queryWhere += fmt.Sprintf(`
+			AND "p"."status" = $%d`, len(b.values))
+	}
+	if !b.req.IsAdmin {
+		queryWhere += `
+			AND ` + products.PublishedQuery
 	}
 
 	// Search check
-	if b.req.Search != "" {
-		b.values = append(b.values, b.req.Search)
-		queryWhereStack = append(queryWhereStack, `
-			AND (LOWER("p"."title")) LIKE ? OR LOWER("p"."description") LIKE ?`,
-		)
+	if tsQuery := searchQuery(b.req.Search); tsQuery != "" {
+		b.values = append(b.values, tsQuery)
+		queryWhere += fmt.Sprintf(`
+			AND "p"."search" @@ to_tsquery('simple', $%d)`, len(b.values))
+		b.searchIndex = len(b.values)
 	}
 
-	for i := range queryWhereStack {
-		if i != len(queryWhereStack)-1 {
-			queryWhere += strings.Replace(queryWhereStack[i], "?", "$"+strconv.Itoa(i+1), 1)
-		} else {
-			queryWhere += strings.Replace(queryWhereStack[i], "?", "$"+strconv.Itoa(i+1), 1)
-			queryWhere = strings.Replace(queryWhere, "?", "$"+strconv.Itoa(i+2), 1)
-		}
+	// Category check, any of the categories or their descendants
+	if len(b.req.CategoryId) > 0 && except != "category" {
+		b.values = append(b.values, b.req.CategoryId)
+		queryWhere += fmt.Sprintf(`
+			AND EXISTS (
+				SELECT 1
+				FROM "products_categories" "fpc"
+					INNER JOIN "categories_closure" "fcc" ON "fcc"."descendant_id" = "fpc"."category_id"
+				WHERE "fpc"."product_id" = "p"."id"
+				AND "fcc"."ancestor_id" = ANY($%d::INT[])
+			)`, len(b.values))
 	}
 
-	// Update lastStackIndex
-	b.lastStackIndex = len(b.values)
+	// Price range check
+	if b.req.MinPrice > 0 && except != "price" {
+		b.values = append(b.values, b.req.MinPrice)
+		queryWhere += fmt.Sprintf(`
+			AND "p"."price" >= $%d`, len(b.values))
+	}
+	if b.req.MaxPrice > 0 && except != "price" {
+		b.values = append(b.values, b.req.MaxPrice)
+		queryWhere += fmt.Sprintf(`
+			AND "p"."price" <= $%d`, len(b.values))
+	}
 
-	// Summary query
-	b.query += queryWhere
-}
+	// In stock check
+	if b.req.InStock {
+		queryWhere += `
+			AND COALESCE("p"."stock" > 0, TRUE)`
+	}
 
-func (b *findProductBuilder) sort() {
-	orderByMap := map[string]string{
-		"id":    "\"p\".\"id\"",
-		"title": "\"p\".\"title\"",
-		"price": "\"p\".\"price\"",
+	// Rating check
+	if b.req.MinRating > 0 {
+		b.values = append(b.values, b.req.MinRating)
+		queryWhere += fmt.Sprintf(`
+			AND "p"."rating" >= $%d`, len(b.values))
 	}
-	if orderByMap[b.req.SortBy] == "" {
-		b.req.OrderBy = orderByMap["title"]
-	} else {
-		b.req.OrderBy = orderByMap[b.req.OrderBy]
+
+	// Attributes check, any of the values of every attribute
+	for _, name := range slices.Sorted(maps.Keys(b.req.Attributes)) {
+		b.values = append(b.values, name, b.req.Attributes[name])
+		queryWhere += fmt.Sprintf(`
+			AND "p"."attributes"->>$%d = ANY($%d::VARCHAR[])`, len(b.values)-1, len(b.values))
 	}
 
-	sortMap := map[string]string{
-		"ASC":  "ASC",
-		"DESC": "DESC",
+	// Date added check
+	if b.req.StartDate != "" {
+		b.values = append(b.values, b.req.StartDate)
+		queryWhere += fmt.Sprintf(`
+			AND "p"."created_at" >= DATE($%d)`, len(b.values))
 	}
-	if sortMap[b.req.SortBy] != "" {
-		b.req.SortBy = sortMap["ASC"]
-	} else {
-		b.req.SortBy = sortMap[strings.ToUpper(b.req.SortBy)]
+	if b.req.EndDate != "" {
+		b.values = append(b.values, b.req.EndDate)
+		queryWhere += fmt.Sprintf(`
+			AND "p"."created_at" < ($%d)::DATE + 1`, len(b.values))
 	}
 
-	b.values = append(b.values, b.req.OrderBy)
-	b.query += fmt.Sprintf(
-		`ORDER BY $%d %s`,
-		b.lastStackIndex+1,
-		b.req.SortBy,
-	)
-	b.lastStackIndex = len(b.values)
+	return queryWhere
+}
+
+// searchQuery turns the search text into a tsquery, every word has to match
+// and the last letters may be missing, e.g. "cof bea" -> "cof:* & bea:*".
+func searchQuery(search string) string {
+	words := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r)
+	})
+
+	for i := range words {
+		words[i] += ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+func (b *findProductBuilder) sort() {
+	columns := maps.Clone(products.SortFields)
+
+	// Title matches weigh more than description matches
+	columns["relevance"] = `"p"."title"`
+	if b.searchIndex > 0 {
+		columns["relevance"] = fmt.Sprintf(`ts_rank("p"."search", to_tsquery('simple', $%d))`, b.searchIndex)
+	}
+
+	b.query += b.req.SortReq.Query(columns, `"p"."id" ASC`)
 }
 
 func (b *findProductBuilder) paginate() {
@@ -179,16 +290,115 @@ func (b *findProductBuilder) paginate() {
 	b.lastStackIndex = len(b.values)
 }
 
+// cursor replaces sort and paginate in cursor mode, it takes one more row to
+// know if there is another page.
+func (b *findProductBuilder) cursor() {
+	column := `"p"."` + b.req.OrderBy + `"`
+
+	sortBy, compare := "ASC", ">"
+	if strings.ToUpper(b.req.SortBy) == "DESC" {
+		sortBy, compare = "DESC", "<"
+	}
+	// A before page walks backward, the rows are reversed later
+	if b.req.IsBefore() {
+		if sortBy == "ASC" {
+			sortBy, compare = "DESC", "<"
+		} else {
+			sortBy, compare = "ASC", ">"
+		}
+	}
+
+	if b.req.Key != nil {
+		b.values = append(b.values, b.req.Key.Value, b.req.Key.Id)
+		b.query += fmt.Sprintf(`
+			AND (%s, "p"."id") %s (($%d)::TEXT::%s, $%d)`,
+			column,
+			compare,
+			b.lastStackIndex+1,
+			products.CursorOrderBy[b.req.OrderBy],
+			b.lastStackIndex+2,
+		)
+	}
+
+	b.values = append(b.values, b.req.Limit+1)
+	b.query += fmt.Sprintf(`
+		ORDER BY %s %s, "p"."id" %s
+		LIMIT $%d`,
+		column,
+		sortBy,
+		sortBy,
+		len(b.values),
+	)
+	b.lastStackIndex = len(b.values)
+}
+
 func (b *findProductBuilder) closeJsonQuery() {
 	b.query += `
 		) AS "t";
 	`
 }
 
+func (b *findProductBuilder) facetQuery() {
+	priceBuckets := ""
+	for i := range products.PriceBuckets {
+		if i != len(products.PriceBuckets)-1 {
+			priceBuckets += fmt.Sprintf(`(%v::FLOAT, %v::FLOAT),`, products.PriceBuckets[i], products.PriceBuckets[i+1])
+		} else {
+			priceBuckets += fmt.Sprintf(`(%v::FLOAT, NULL::FLOAT)`, products.PriceBuckets[i])
+		}
+	}
+
+	b.query += `
+		SELECT
+			jsonb_build_object(
+				'categories', (
+					SELECT
+						COALESCE(array_to_json(array_agg("ct")), '[]'::json)
+					FROM (
+						SELECT
+							"c"."id",
+							"c"."title",
+							"c"."parent_id",
+							COUNT(DISTINCT "p"."id") AS "count"
+						FROM "products" "p"
+							INNER JOIN "products_categories" "pc" ON "pc"."product_id" = "p"."id"
+							INNER JOIN "categories_closure" "cc" ON "cc"."descendant_id" = "pc"."category_id"
+							INNER JOIN "categories" "c" ON "c"."id" = "cc"."ancestor_id"
+						WHERE 1 = 1` + b.whereStack("category") + `
+						GROUP BY "c"."id", "c"."title", "c"."parent_id"
+						ORDER BY "c"."title"
+					) AS "ct"
+				),
+				'prices', (
+					SELECT
+						COALESCE(array_to_json(array_agg("bt")), '[]'::json)
+					FROM (
+						SELECT
+							"b"."min",
+							"b"."max",
+							COUNT("fp"."id") AS "count"
+						FROM (VALUES ` + priceBuckets + `) AS "b" ("min", "max")
+							LEFT JOIN (
+								SELECT
+									"p"."id",
+									"p"."price"
+								FROM "products" "p"
+								WHERE 1 = 1` + b.whereStack("price") + `
+							) AS "fp" ON "fp"."price" >= "b"."min" AND ("b"."max" IS NULL OR "fp"."price" < "b"."max")
+						GROUP BY "b"."min", "b"."max"
+						ORDER BY "b"."min"
+					) AS "bt"
+				)
+			);
+	`
+	b.lastStackIndex = len(b.values)
+}
+
 func (b *findProductBuilder) resetQuery() {
 	b.query = ""
 	b.values = make([]any, 0)
 	b.lastStackIndex = 0
+	b.searchIndex = 0
 }
 
 func (b *findProductBuilder) Result() []*products.Product {
@@ -227,6 +437,29 @@ func (b *findProductBuilder) Count() int {
 	return count
 }
 
+func (b *findProductBuilder) Facets() *products.Facets {
+	_, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	bytes := make([]byte, 0)
+	facets := &products.Facets{
+		Categories: make([]*products.CategoryFacet, 0),
+		Prices:     make([]*products.PriceFacet, 0),
+	}
+	if err := b.db.Get(&bytes, b.query, b.values...); err != nil {
+		log.Printf("Error find product facets: %v", err)
+		return facets
+	}
+
+	if err := json.Unmarshal(bytes, &facets); err != nil {
+		log.Printf("Error unmarshal product facets: %v", err)
+	}
+
+	b.resetQuery()
+
+	return facets
+}
+
 func (b *findProductBuilder) PrintQuery() {
 	utils.Debug(b.values)
 	fmt.Println(b.query)
@@ -252,8 +485,22 @@ func (en *findProductEngineer) FindProduct() IFindProductBuilder {
 	return en.builder
 }
 
+func (en *findProductEngineer) FindProductCursor() IFindProductBuilder {
+	en.builder.openJsonQuert()
+	en.builder.initQuery()
+	en.builder.whereQuery()
+	en.builder.cursor()
+	en.builder.closeJsonQuery()
+	return en.builder
+}
+
 func (en *findProductEngineer) CountProduct() IFindProductBuilder {
 	en.builder.countQuery()
 	en.builder.whereQuery()
 	return en.builder
 }
+
+func (en *findProductEngineer) FindFacet() IFindProductBuilder {
+	en.builder.facetQuery()
+	return en.builder
+}
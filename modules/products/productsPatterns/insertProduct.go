@@ -2,9 +2,13 @@ package productsPatterns
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
 	"github.com/jmoiron/sqlx"
 )
@@ -13,7 +17,11 @@ type IInsertProductBuilder interface {
 	initTransaction() error
 	insertProduct() error
 	insertCategory() error
+	insertAttributes() error
 	insertAttachment() error
+	insertOptions() error
+	insertVariants() error
+	insertStock() error
 	commit() error
 	getProductId() string
 }
@@ -52,19 +60,46 @@ func (b *insertProductBuilder) insertProduct() error {
 		INSERT INTO products (
 			"title",
 			"description",
-			"price"
+			"price",
+			"stock",
+			"status",
+			"publish_at",
+			"unpublish_at",
+			"sku",
+			"slug"
 		)
 		VALUES
-			($1, $2, $3)
+			(
+				$1, $2, $3, $4,
+				COALESCE(NULLIF($5, ''), 'active')::product_status,
+				(NULLIF($6, '')::TIMESTAMPTZ)::TIMESTAMP,
+				(NULLIF($7, '')::TIMESTAMPTZ)::TIMESTAMP,
+				NULLIF($8, ''),
+				$9
+			)
 		RETURNING "id";
 	`
 
+	// A new product tracks its stock only when available_qty is sent, NULL
+	// is untracked, the stock of a product with variants comes from its
+	// variants
+	var stock *int
+	if len(b.req.Variants) == 0 {
+		stock = b.req.AvailableQty
+	}
+
 	if err := b.tx.QueryRowxContext(
 		ctx,
 		query,
 		b.req.Title,
 		b.req.Description,
 		b.req.Price,
+		stock,
+		b.req.Status,
+		b.req.PublishAt,
+		b.req.UnpublishAt,
+		b.req.Sku,
+		b.req.Slug,
 	).Scan(&b.req.Id); err != nil {
 		b.tx.Rollback()
 		return fmt.Errorf("failed to insert product: %w", err)
@@ -76,29 +111,31 @@ func (b *insertProductBuilder) insertCategory() error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
 	defer cancel()
 
-	query := `
-		INSERT INTO "products_categories" (
-			"product_id",
-			"category_id"
-		)
-		VALUES
-			($1, $2);
-	`
+	if err := insertCategories(ctx, b.tx, b.req.Id, categoryIds(b.req)); err != nil {
+		b.tx.Rollback()
+		return err
+	}
 
-	if _, err := b.tx.ExecContext(
-		ctx,
-		query,
-		b.req.Id,
-		b.req.Category.Id,
-	); err != nil {
+	return nil
+}
+
+func (b *insertProductBuilder) insertAttributes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	if err := setAttributes(ctx, b.tx, b.req.Id, b.req.Attributes, false); err != nil {
 		b.tx.Rollback()
-		return fmt.Errorf("failed to insert category: %w", err)
+		return err
 	}
 
 	return nil
 }
 
 func (b *insertProductBuilder) insertAttachment() error {
+	if len(b.req.Images) == 0 {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
 	defer cancel()
 
@@ -106,6 +143,8 @@ func (b *insertProductBuilder) insertAttachment() error {
 		INSERT INTO "images" (
 			"filename",
 			"url",
+			"alt",
+			"position",
 			"product_id"
 		)
 		VALUES
@@ -118,15 +157,17 @@ func (b *insertProductBuilder) insertAttachment() error {
 			valueStack,
 			b.req.Images[i].Filename,
 			b.req.Images[i].Url,
+			b.req.Images[i].Alt,
+			i,
 			b.req.Id,
 		)
 
 		if i != len(b.req.Images)-1 {
-			query += fmt.Sprintf(`($%d, $%d, $%d),`, index+1, index+2, index+3)
+			query += fmt.Sprintf(`($%d, $%d, $%d, $%d, $%d),`, index+1, index+2, index+3, index+4, index+5)
 		} else {
-			query += fmt.Sprintf(`($%d, $%d, $%d);`, index+1, index+2, index+3)
+			query += fmt.Sprintf(`($%d, $%d, $%d, $%d, $%d);`, index+1, index+2, index+3, index+4, index+5)
 		}
-		index += 3
+		index += 5
 	}
 
 	if _, err := b.tx.ExecContext(
@@ -141,6 +182,273 @@ func (b *insertProductBuilder) insertAttachment() error {
 	return nil
 }
 
+func (b *insertProductBuilder) insertOptions() error {
+	if len(b.req.Options) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	query := `
+		INSERT INTO "products_options" (
+			"product_id",
+			"name",
+			"values",
+			"position"
+		)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING "id";
+	`
+
+	for i := range b.req.Options {
+		if err := b.tx.QueryRowxContext(
+			ctx,
+			query,
+			b.req.Id,
+			b.req.Options[i].Name,
+			b.req.Options[i].Values,
+			i,
+		).Scan(&b.req.Options[i].Id); err != nil {
+			b.tx.Rollback()
+			return fmt.Errorf("failed to insert option: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (b *insertProductBuilder) insertVariants() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	for i := range b.req.Variants {
+		if err := insertVariant(ctx, b.tx, b.req.Id, b.req.Variants[i]); err != nil {
+			b.tx.Rollback()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *insertProductBuilder) insertStock() error {
+	// Variants write their own initial stock
+	if len(b.req.Variants) > 0 || b.req.AvailableQty == nil || *b.req.AvailableQty <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	query := `
+		INSERT INTO "products_stocks" (
+			"product_id",
+			"qty",
+			"reason"
+		)
+		VALUES
+			($1, $2, 'initial stock');
+	`
+
+	if _, err := b.tx.ExecContext(
+		ctx,
+		query,
+		b.req.Id,
+		*b.req.AvailableQty,
+	); err != nil {
+		b.tx.Rollback()
+		return fmt.Errorf("failed to insert stock: %w", err)
+	}
+
+	return nil
+}
+
+// categoryIds puts the main category first, followed by the other categories.
+func categoryIds(req *products.Product) []int {
+	ids := make([]int, 0)
+	seen := make(map[int]bool)
+	if req.Category != nil && req.Category.Id > 0 {
+		ids = append(ids, req.Category.Id)
+		seen[req.Category.Id] = true
+	}
+	for _, c := range req.Categories {
+		if c == nil || c.Id <= 0 || seen[c.Id] {
+			continue
+		}
+		ids = append(ids, c.Id)
+		seen[c.Id] = true
+	}
+	return ids
+}
+
+// insertCategories adds the categories in order, the first one becomes the
+// main category. It doesn't roll back the transaction by itself.
+func insertCategories(ctx context.Context, tx *sqlx.Tx, productId string, ids []int) error {
+	query := `
+		INSERT INTO "products_categories" (
+			"product_id",
+			"category_id",
+			"created_at"
+		)
+		VALUES
+			($1, $2, clock_timestamp())
+		ON CONFLICT ("product_id", "category_id") DO UPDATE SET
+			"created_at" = EXCLUDED."created_at";
+	`
+
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, query, productId, id); err != nil {
+			return fmt.Errorf("failed to insert category: %w", err)
+		}
+	}
+	return nil
+}
+
+// setAttributes checks the attribute values against the attributes of the
+// product categories and their ancestors and stores them, a null or empty
+// value is dropped. With prune a value that no longer fits its attribute is
+// dropped too instead of failing, e.g. after the categories change. It
+// doesn't roll back the transaction by itself.
+func setAttributes(ctx context.Context, tx *sqlx.Tx, productId string, attributes map[string]any, prune bool) error {
+	// The closest category wins when two define the same name, the main
+	// category first
+	query := `
+		SELECT
+			COALESCE(array_to_json(array_agg("t")), '[]'::json)
+		FROM (
+			SELECT DISTINCT ON ("a"."name")
+				"a"."name",
+				"a"."type",
+				"a"."values",
+				"a"."is_required"
+			FROM "products_categories" "pc"
+				INNER JOIN "categories_closure" "cc" ON "cc"."descendant_id" = "pc"."category_id"
+				INNER JOIN "categories_attributes" "a" ON "a"."category_id" = "cc"."ancestor_id"
+			WHERE "pc"."product_id" = $1
+			ORDER BY "a"."name", "cc"."depth", "pc"."created_at"
+		) AS "t";
+	`
+
+	raw := make([]byte, 0)
+	if err := tx.GetContext(ctx, &raw, query, productId); err != nil {
+		return fmt.Errorf("failed to get attributes: %w", err)
+	}
+
+	definitions := make([]*appinfo.Attribute, 0)
+	if err := json.Unmarshal(raw, &definitions); err != nil {
+		return fmt.Errorf("failed to unmarshal attributes: %w", err)
+	}
+
+	values := make(map[string]any)
+	for name, value := range attributes {
+		if value == nil || value == "" {
+			continue
+		}
+
+		i := slices.IndexFunc(definitions, func(a *appinfo.Attribute) bool { return a.Name == name })
+		if i == -1 {
+			if prune {
+				continue
+			}
+			return fmt.Errorf("attribute %s is not defined for the product categories", name)
+		}
+
+		if err := checkAttribute(definitions[i], value); err != nil {
+			if prune {
+				continue
+			}
+			return err
+		}
+		values[name] = value
+	}
+
+	for _, a := range definitions {
+		if _, ok := values[a.Name]; a.IsRequired && !ok {
+			return fmt.Errorf("attribute %s is required", a.Name)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE "products" SET "attributes" = $2 WHERE "id" = $1;`, productId, values); err != nil {
+		return fmt.Errorf("failed to update attributes: %w", err)
+	}
+	return nil
+}
+
+// checkAttribute checks the value has the type of the attribute, JSON numbers
+// are float64
+func checkAttribute(attribute *appinfo.Attribute, value any) error {
+	switch attribute.Type {
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("attribute %s must be a number", attribute.Name)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("attribute %s must be a boolean", attribute.Name)
+		}
+	case "select":
+		if v, ok := value.(string); !ok || !slices.Contains(attribute.Values, v) {
+			return fmt.Errorf("attribute %s must be one of %s", attribute.Name, strings.Join(attribute.Values, ", "))
+		}
+	default:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("attribute %s must be a text", attribute.Name)
+		}
+	}
+	return nil
+}
+
+// insertVariant is shared by the insert and update builders, it doesn't roll
+// back the transaction by itself.
+func insertVariant(ctx context.Context, tx *sqlx.Tx, productId string, variant *products.Variant) error {
+	query := `
+		WITH "variant" AS (
+			INSERT INTO "products_variants" (
+				"product_id",
+				"sku",
+				"options",
+				"price",
+				"stock",
+				"image"
+			)
+			VALUES
+				($1, $2, $3, $4, $5, $6)
+			RETURNING "id", "product_id", "stock"
+		), "stock" AS (
+			INSERT INTO "products_stocks" (
+				"product_id",
+				"variant_id",
+				"qty",
+				"reason"
+			)
+			SELECT
+				"v"."product_id",
+				"v"."id",
+				"v"."stock",
+				'initial stock'
+			FROM "variant" "v"
+			WHERE "v"."stock" > 0
+		)
+		SELECT "id" FROM "variant";
+	`
+
+	if err := tx.QueryRowxContext(
+		ctx,
+		query,
+		productId,
+		variant.Sku,
+		variant.Options,
+		variant.Price,
+		variant.AvailableQty,
+		variant.Image,
+	).Scan(&variant.Id); err != nil {
+		return fmt.Errorf("failed to insert variant %s: %w", variant.Sku, err)
+	}
+	return nil
+}
+
 func (b *insertProductBuilder) commit() error {
 	if err := b.tx.Commit(); err != nil {
 		b.tx.Rollback()
@@ -172,10 +480,26 @@ func (en *insertProductEngineer) InsertProduct() (string, error) {
 		return "", err
 	}
 
+	if err := en.builder.insertAttributes(); err != nil {
+		return "", err
+	}
+
 	if err := en.builder.insertAttachment(); err != nil {
 		return "", err
 	}
 
+	if err := en.builder.insertOptions(); err != nil {
+		return "", err
+	}
+
+	if err := en.builder.insertVariants(); err != nil {
+		return "", err
+	}
+
+	if err := en.builder.insertStock(); err != nil {
+		return "", err
+	}
+
 	if err := en.builder.commit(); err != nil {
 		return "", err
 	}
@@ -2,6 +2,7 @@ package productsPatterns
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
@@ -15,12 +16,20 @@ type IUpdateProductBuilder interface {
 	initTransaction() error
 	initQuery()
 	updateTitleQuery()
+	updateSlugQuery()
+	updateSkuQuery()
 	updateDescriptionQuery()
 	updatePriceQuery()
+	updateStatusQuery()
+	updatePublishQuery()
 	updateCategory() error
+	updateAttributes() error
 	insertImages() error
 	getOldImages() []*entities.Image
 	deleteOldImages() error
+	deleteOldFiles()
+	updateOptions() error
+	updateVariants() error
 	closeQuery()
 	updateProduct() error
 	getQueryFields() []string
@@ -36,6 +45,7 @@ type updateProductBuilder struct {
 	tx             *sqlx.Tx
 	req            *products.Product
 	filesUsecases  filesUsecases.IFilesUsecase
+	deleteFileReq  []*files.DeleteFileReq
 	query          string
 	queryFields    []string
 	lastStackIndex int
@@ -83,6 +93,30 @@ func (b *updateProductBuilder) updateTitleQuery() {
 	}
 }
 
+func (b *updateProductBuilder) updateSlugQuery() {
+	if b.req.Slug != "" {
+		b.values = append(b.values, b.req.Slug)
+
+		b.queryFields = append(b.queryFields, fmt.Sprintf(
+			`"slug" = $%d`,
+			b.lastStackIndex+1),
+		)
+		b.lastStackIndex = len(b.values)
+	}
+}
+
+func (b *updateProductBuilder) updateSkuQuery() {
+	if b.req.Sku != "" {
+		b.values = append(b.values, b.req.Sku)
+
+		b.queryFields = append(b.queryFields, fmt.Sprintf(
+			`"sku" = $%d`,
+			b.lastStackIndex+1),
+		)
+		b.lastStackIndex = len(b.values)
+	}
+}
+
 func (b *updateProductBuilder) updateDescriptionQuery() {
 	if b.req.Description != "" {
 		b.values = append(b.values, b.req.Description)
@@ -107,7 +141,70 @@ func (b *updateProductBuilder) updatePriceQuery() {
 	}
 }
 
+func (b *updateProductBuilder) updateStatusQuery() {
+	if b.req.Status != "" {
+		b.values = append(b.values, b.req.Status)
+
+		b.queryFields = append(b.queryFields, fmt.Sprintf(
+			`"status" = $%d`,
+			b.lastStackIndex+1),
+		)
+		b.lastStackIndex = len(b.values)
+	}
+}
+
+// updatePublishQuery sets the publish window, an empty time clears its side
+func (b *updateProductBuilder) updatePublishQuery() {
+	if b.req.PublishAt != nil {
+		b.values = append(b.values, *b.req.PublishAt)
+
+		b.queryFields = append(b.queryFields, fmt.Sprintf(
+			`"publish_at" = (NULLIF($%d, '')::TIMESTAMPTZ)::TIMESTAMP`,
+			b.lastStackIndex+1),
+		)
+		b.lastStackIndex = len(b.values)
+	}
+
+	if b.req.UnpublishAt != nil {
+		b.values = append(b.values, *b.req.UnpublishAt)
+
+		b.queryFields = append(b.queryFields, fmt.Sprintf(
+			`"unpublish_at" = (NULLIF($%d, '')::TIMESTAMPTZ)::TIMESTAMP`,
+			b.lastStackIndex+1),
+		)
+		b.lastStackIndex = len(b.values)
+	}
+}
+
 func (b *updateProductBuilder) updateCategory() error {
+	ctx := context.Background()
+
+	// Categories replace the whole set, in their order
+	if b.req.Categories != nil {
+		ids := categoryIds(b.req)
+		if len(ids) == 0 {
+			b.tx.Rollback()
+			return fmt.Errorf("product must have at least one category")
+		}
+
+		query := `
+			DELETE FROM "products_categories"
+			WHERE "product_id" = $1
+			AND NOT ("category_id" = ANY($2::INT[]));
+		`
+
+		if _, err := b.tx.ExecContext(ctx, query, b.req.Id, ids); err != nil {
+			b.tx.Rollback()
+			return fmt.Errorf("failed to update category: %w", err)
+		}
+
+		if err := insertCategories(ctx, b.tx, b.req.Id, ids); err != nil {
+			b.tx.Rollback()
+			return err
+		}
+		return nil
+	}
+
 	if b.req.Category == nil {
 		return nil
 	}
@@ -115,14 +212,34 @@ func (b *updateProductBuilder) updateCategory() error {
 		return nil
 	}
 
+	// Category alone replaces the main category only
 	query := `
-		UPDATE "products_categories" SET
-			"category_id" = $1
-		WHERE "product_id" = $2;
+		WITH "main" AS (
+			SELECT
+				"id",
+				"created_at"
+			FROM "products_categories"
+			WHERE "product_id" = $2
+			ORDER BY "created_at", "id"
+			LIMIT 1
+		), "replaced" AS (
+			DELETE FROM "products_categories"
+			WHERE "id" = (SELECT "id" FROM "main")
+			AND "category_id" != $1
+		)
+		INSERT INTO "products_categories" (
+			"product_id",
+			"category_id",
+			"created_at"
+		)
+		VALUES
+			($2, $1, COALESCE((SELECT "created_at" FROM "main"), now()))
+		ON CONFLICT ("product_id", "category_id") DO UPDATE SET
+			"created_at" = EXCLUDED."created_at";
 	`
 
 	if _, err := b.tx.ExecContext(
-		context.Background(),
+		ctx,
 		query,
 		b.req.Category.Id,
 		b.req.Id,
@@ -133,11 +250,44 @@ func (b *updateProductBuilder) updateCategory() error {
 
 	return nil
 }
+
+// updateAttributes replaces the attribute values, the stored ones are checked
+// again when the categories change
+func (b *updateProductBuilder) updateAttributes() error {
+	categoryChanged := b.req.Categories != nil || (b.req.Category != nil && b.req.Category.Id != 0)
+	if b.req.Attributes == nil && !categoryChanged {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	attributes := b.req.Attributes
+	if attributes == nil {
+		raw := make([]byte, 0)
+		if err := b.tx.GetContext(ctx, &raw, `SELECT "attributes" FROM "products" WHERE "id" = $1;`, b.req.Id); err != nil {
+			b.tx.Rollback()
+			return fmt.Errorf("failed to get attributes: %w", err)
+		}
+		if err := json.Unmarshal(raw, &attributes); err != nil {
+			b.tx.Rollback()
+			return fmt.Errorf("failed to unmarshal attributes: %w", err)
+		}
+	}
+
+	if err := setAttributes(ctx, b.tx, b.req.Id, attributes, b.req.Attributes == nil); err != nil {
+		b.tx.Rollback()
+		return err
+	}
+	return nil
+}
+
 func (b *updateProductBuilder) insertImages() error {
 	query := `
 		INSERT INTO "images" (
 			"filename",
 			"url",
+			"alt",
+			"position",
 			"product_id"
 		)
 		VALUES
@@ -150,15 +300,17 @@ func (b *updateProductBuilder) insertImages() error {
 			valueStack,
 			b.req.Images[i].Filename,
 			b.req.Images[i].Url,
+			b.req.Images[i].Alt,
+			i,
 			b.req.Id,
 		)
 
 		if i != len(b.req.Images)-1 {
-			query += fmt.Sprintf(`($%d, $%d, $%d),`, index+1, index+2, index+3)
+			query += fmt.Sprintf(`($%d, $%d, $%d, $%d, $%d),`, index+1, index+2, index+3, index+4, index+5)
 		} else {
-			query += fmt.Sprintf(`($%d, $%d, $%d);`, index+1, index+2, index+3)
+			query += fmt.Sprintf(`($%d, $%d, $%d, $%d, $%d);`, index+1, index+2, index+3, index+4, index+5)
 		}
-		index += 3
+		index += 5
 	}
 
 	if _, err := b.tx.ExecContext(
@@ -178,7 +330,8 @@ func (b *updateProductBuilder) getOldImages() []*entities.Image {
 		SELECT
 			"id",
 			"filename",
-			"url"
+			"url",
+			"alt"
 		FROM "images"
 		WHERE "product_id" = $1
 	`
@@ -195,16 +348,36 @@ func (b *updateProductBuilder) deleteOldImages() error {
 		DELETE FROM "images"
 		WHERE "product_id" = $1;
 	`
+
+	// Files that the new images still point to are kept
+	kept := make(map[string]bool)
+	for _, img := range b.req.Images {
+		kept[img.Url] = true
+	}
+
 	images := b.getOldImages()
 	if len(images) > 0 {
-		deleteFileReq := make([]*files.DeleteFileReq, 0)
+		// An image that stays keeps its alt when none is sent, like the
+		// images of a re-imported export
+		alts := make(map[string]string)
+		for _, img := range images {
+			alts[img.Url] = img.Alt
+		}
+		for _, img := range b.req.Images {
+			if img.Alt == "" {
+				img.Alt = alts[img.Url]
+			}
+		}
+
+		// The files are deleted after the commit, a rollback still needs them
 		for _, img := range images {
-			deleteFileReq = append(deleteFileReq, &files.DeleteFileReq{
+			if kept[img.Url] {
+				continue
+			}
+			b.deleteFileReq = append(b.deleteFileReq, &files.DeleteFileReq{
 				Destination: fmt.Sprintf("images/products/%s", img.Filename),
 			})
 		}
-
-		b.filesUsecases.DeleteFile(deleteFileReq)
 	}
 
 	if _, err := b.tx.ExecContext(
@@ -218,6 +391,133 @@ func (b *updateProductBuilder) deleteOldImages() error {
 	return nil
 }
 
+func (b *updateProductBuilder) deleteOldFiles() {
+	if len(b.deleteFileReq) > 0 {
+		b.filesUsecases.DeleteFile(b.deleteFileReq)
+	}
+}
+
+func (b *updateProductBuilder) updateOptions() error {
+	// Options are replaced as a whole, only when they are sent
+	if b.req.Options == nil {
+		return nil
+	}
+
+	query := `
+		DELETE FROM "products_options"
+		WHERE "product_id" = $1;
+	`
+
+	if _, err := b.tx.ExecContext(
+		context.Background(),
+		query,
+		b.req.Id,
+	); err != nil {
+		b.tx.Rollback()
+		return fmt.Errorf("failed to delete old options: %w", err)
+	}
+
+	query = `
+		INSERT INTO "products_options" (
+			"product_id",
+			"name",
+			"values",
+			"position"
+		)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING "id";
+	`
+
+	for i := range b.req.Options {
+		if err := b.tx.QueryRowxContext(
+			context.Background(),
+			query,
+			b.req.Id,
+			b.req.Options[i].Name,
+			b.req.Options[i].Values,
+			i,
+		).Scan(&b.req.Options[i].Id); err != nil {
+			b.tx.Rollback()
+			return fmt.Errorf("failed to insert option: %w", err)
+		}
+	}
+	return nil
+}
+
+func (b *updateProductBuilder) updateVariants() error {
+	// Variants are synced only when they are sent, the ones that are not in the
+	// request are archived, so their stock ledger and the reservations of open
+	// orders stay, the ones without id are created
+	if b.req.Variants == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	queryArchive := `
+		UPDATE "products_variants" SET
+			"deleted_at" = now()
+		WHERE "product_id" = $1
+		AND "deleted_at" IS NULL
+	`
+	values := []any{b.req.Id}
+	for i := range b.req.Variants {
+		if b.req.Variants[i].Id == "" {
+			continue
+		}
+		values = append(values, b.req.Variants[i].Id)
+		queryArchive += fmt.Sprintf(` AND "id" != $%d`, len(values))
+	}
+
+	if _, err := b.tx.ExecContext(ctx, queryArchive, values...); err != nil {
+		b.tx.Rollback()
+		return fmt.Errorf("failed to archive old variants: %w", err)
+	}
+
+	// Stock is changed by the stock adjustment only
+	queryUpdate := `
+		UPDATE "products_variants" SET
+			"sku" = $1,
+			"options" = $2,
+			"price" = $3,
+			"image" = $4
+		WHERE "id" = $5
+		AND "product_id" = $6
+		AND "deleted_at" IS NULL;
+	`
+
+	for i := range b.req.Variants {
+		if b.req.Variants[i].Id == "" {
+			if err := insertVariant(ctx, b.tx, b.req.Id, b.req.Variants[i]); err != nil {
+				b.tx.Rollback()
+				return err
+			}
+			continue
+		}
+
+		result, err := b.tx.ExecContext(
+			ctx,
+			queryUpdate,
+			b.req.Variants[i].Sku,
+			b.req.Variants[i].Options,
+			b.req.Variants[i].Price,
+			b.req.Variants[i].Image,
+			b.req.Variants[i].Id,
+			b.req.Id,
+		)
+		if err != nil {
+			b.tx.Rollback()
+			return fmt.Errorf("failed to update variant %s: %w", b.req.Variants[i].Sku, err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			b.tx.Rollback()
+			return fmt.Errorf("variant %s not found", b.req.Variants[i].Id)
+		}
+	}
+	return nil
+}
+
 func (b *updateProductBuilder) closeQuery() {
 	b.values = append(b.values, b.req.Id)
 	b.lastStackIndex = len(b.values)
@@ -279,8 +579,12 @@ func UpdateProductEngineer(builder IUpdateProductBuilder) *updateProductEngineer
 
 func (en *updateProductEngineer) sumQueryFields() {
 	en.builder.updateTitleQuery()
+	en.builder.updateSlugQuery()
+	en.builder.updateSkuQuery()
 	en.builder.updateDescriptionQuery()
 	en.builder.updatePriceQuery()
+	en.builder.updateStatusQuery()
+	en.builder.updatePublishQuery()
 
 	fields := en.builder.getQueryFields()
 
@@ -302,8 +606,10 @@ func (en *updateProductEngineer) UpdateProduct() error {
 	en.builder.closeQuery()
 
 	// Update product
-	if err := en.builder.updateProduct(); err != nil {
-		return err
+	if len(en.builder.getQueryFields()) > 0 {
+		if err := en.builder.updateProduct(); err != nil {
+			return err
+		}
 	}
 
 	// Update Category
@@ -311,6 +617,11 @@ func (en *updateProductEngineer) UpdateProduct() error {
 		return err
 	}
 
+	// Update attributes
+	if err := en.builder.updateAttributes(); err != nil {
+		return err
+	}
+
 	if en.builder.getImagesLen() > 0 {
 		// Delete old images
 		if err := en.builder.deleteOldImages(); err != nil {
@@ -323,9 +634,21 @@ func (en *updateProductEngineer) UpdateProduct() error {
 		}
 	}
 
+	// Update options and variants
+	if err := en.builder.updateOptions(); err != nil {
+		return err
+	}
+
+	if err := en.builder.updateVariants(); err != nil {
+		return err
+	}
+
 	// Commit
 	if err := en.builder.commit(); err != nil {
 		return err
 	}
+
+	// Delete the files of the replaced images
+	en.builder.deleteOldFiles()
 	return nil
 }
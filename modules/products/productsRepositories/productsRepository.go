@@ -2,11 +2,16 @@ package productsRepositories
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/IzePhanthakarn/go-basic-shop/config"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/files"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/files/filesUsecases"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsPatterns"
@@ -16,9 +21,30 @@ import (
 type IProductsRepository interface {
 	FindOneProduct(productId string) (*products.Product, error)
 	FindProduct(req *products.ProductFilter) ([]*products.Product, int)
+	FindProductCursor(req *products.ProductFilter) ([]*products.Product, int)
+	FindFacet(req *products.ProductFilter) *products.Facets
+	FindProductKeys(ids, skus []string) ([]*products.ProductKey, error)
+	FindProductSlug(slug string) (*products.SlugRes, error)
+	FindSlugs(slug, productId string) ([]string, error)
+	FindCategories() ([]*appinfo.Category, error)
 	InsertProduct(req *products.Product) (*products.Product, error)
 	UpdateProduct(req *products.Product) (*products.Product, error)
 	DeleteProduct(productId string) error
+	PurgeProduct(productId string) error
+	RestoreProduct(productId string) error
+	RotatePreviewToken(productId string) (string, error)
+	RevokePreviewToken(productId string) error
+	AddCategory(productId string, categoryId int) error
+	RemoveCategory(productId string, categoryId int) error
+	AddImage(req *products.ImageReq) error
+	UpdateImage(req *products.ImageReq) error
+	ReorderImage(productId string, imageIds []string) error
+	RemoveImage(productId, imageId string) error
+	FindStock(productId string) ([]*products.StockAdjustment, error)
+	AdjustStock(req *products.StockAdjustment) error
+	FindPrice(productId string) ([]*products.Price, error)
+	SchedulePrice(req *products.PriceReq) error
+	ApplyScheduledPrice() (int, error)
 }
 
 type productsRepository struct {
@@ -42,23 +68,67 @@ func (r *productsRepository) FindOneProduct(productId string) (*products.Product
 	FROM (
 		SELECT
 			"p"."id",
+			"p"."sku",
 			"p"."title",
+			"p"."slug",
 			"p"."description",
+			"p"."attributes",
 			"p"."price",
+			(
+				SELECT
+					CASE WHEN "pp"."compare_at_price" > "p"."price" THEN "pp"."compare_at_price" END
+				FROM "products_prices" "pp"
+				WHERE "pp"."product_id" = "p"."id"
+				AND "pp"."effective_from" <= now()
+				ORDER BY "pp"."effective_from" DESC
+				LIMIT 1
+			) AS "compare_at_price",
+			` + products.FlashSaleQuery + ` AS "flash_sale",
+			"p"."stock" AS "available_qty",
+			COALESCE("p"."stock" > 0, TRUE) AS "in_stock",
+			"p"."rating",
+			"p"."review_count",
 			(
 				SELECT
 					to_jsonb("ct")
 				FROM (
 					SELECT
 						"c"."id",
-						"c"."title"
+						"c"."title",
+						"c"."parent_id",
+						"cb"."breadcrumbs"
 					FROM "categories" "c"
 						LEFT JOIN "products_categories" "pc" ON "pc"."category_id" = "c"."id"
+						LEFT JOIN "categories_breadcrumbs" "cb" ON "cb"."category_id" = "c"."id"
 					WHERE "pc"."product_id" = "p"."id"
+					ORDER BY "pc"."created_at", "pc"."id"
+					LIMIT 1
 				) AS "ct"
 			) AS "category",
+			(
+				SELECT
+					COALESCE(array_to_json(array_agg("cst")), '[]'::json)
+				FROM (
+					SELECT
+						"c"."id",
+						"c"."title",
+						"c"."parent_id",
+						"cb"."breadcrumbs"
+					FROM "categories" "c"
+						INNER JOIN "products_categories" "pc" ON "pc"."category_id" = "c"."id"
+						LEFT JOIN "categories_breadcrumbs" "cb" ON "cb"."category_id" = "c"."id"
+					WHERE "pc"."product_id" = "p"."id"
+					ORDER BY "pc"."created_at", "pc"."id"
+				) AS "cst"
+			) AS "categories",
 			"p"."created_at",
 			"p"."updated_at",
+			"p"."status",
+			"p"."deleted_at",
+			"p"."publish_at",
+			"p"."unpublish_at",
+			` + products.PublishedQuery + ` AS "published",
+			"p"."preview_token",
 			(
 				SELECT
 					COALESCE(array_to_json(array_agg("it")), '[]'::json)
@@ -66,11 +136,44 @@ func (r *productsRepository) FindOneProduct(productId string) (*products.Product
 					SELECT
 						"i"."id",
 						"i"."filename",
-						"i"."url"
+						"i"."url",
+						"i"."alt"
 					FROM "images" "i"
 					WHERE "i"."product_id" = "p"."id"
+					ORDER BY "i"."position", "i"."created_at"
 				) AS "it"
-			) AS "images"
+			) AS "images",
+			(
+				SELECT
+					COALESCE(array_to_json(array_agg("ot")), '[]'::json)
+				FROM (
+					SELECT
+						"o"."id",
+						"o"."name",
+						"o"."values"
+					FROM "products_options" "o"
+					WHERE "o"."product_id" = "p"."id"
+					ORDER BY "o"."position"
+				) AS "ot"
+			) AS "options",
+			(
+				SELECT
+					COALESCE(array_to_json(array_agg("vt")), '[]'::json)
+				FROM (
+					SELECT
+						"v"."id",
+						"v"."sku",
+						"v"."options",
+						"v"."price",
+						("v"."stock" > 0) AS "in_stock",
+						"v"."stock" AS "available_qty",
+						"v"."image"
+					FROM "products_variants" "v"
+					WHERE "v"."product_id" = "p"."id"
+					AND "v"."deleted_at" IS NULL
+					ORDER BY "v"."created_at", "v"."sku"
+				) AS "vt"
+			) AS "variants"
 		FROM "products" "p"
 		WHERE "p"."id" = $1
 		LIMIT 1
@@ -101,6 +204,112 @@ func (r *productsRepository) FindProduct(req *products.ProductFilter) ([]*produc
 	return result, count
 }
 
+// FindProductCursor counts only when the total is asked for.
+func (r *productsRepository) FindProductCursor(req *products.ProductFilter) ([]*products.Product, int) {
+	builder := productsPatterns.FindProductBuilder(r.db, req)
+	engineer := productsPatterns.FindProductEngineer(builder)
+
+	result := engineer.FindProductCursor().Result()
+	if !req.WithTotal {
+		return result, 0
+	}
+	count := engineer.CountProduct().Count()
+	return result, count
+}
+
+func (r *productsRepository) FindFacet(req *products.ProductFilter) *products.Facets {
+	builder := productsPatterns.FindProductBuilder(r.db, req)
+	engineer := productsPatterns.FindProductEngineer(builder)
+
+	return engineer.FindFacet().Facets()
+}
+
+// FindProductKeys finds the products that have any of the ids or skus
+func (r *productsRepository) FindProductKeys(ids, skus []string) ([]*products.ProductKey, error) {
+	query := `
+	SELECT
+		"id",
+		COALESCE("sku", '') AS "sku"
+	FROM "products"
+	WHERE "id" = ANY($1::VARCHAR[])
+	OR "sku" = ANY($2::VARCHAR[]);`
+
+	keys := make([]*products.ProductKey, 0)
+	if err := r.db.Select(&keys, query, ids, skus); err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	return keys, nil
+}
+
+// FindProductSlug finds the product of a current or an old slug, the current
+// slug comes first
+func (r *productsRepository) FindProductSlug(slug string) (*products.SlugRes, error) {
+	query := `
+	SELECT
+		"id",
+		"slug",
+		FALSE AS "moved"
+	FROM "products"
+	WHERE "slug" = $1
+	UNION ALL
+	SELECT
+		"p"."id",
+		"p"."slug",
+		TRUE AS "moved"
+	FROM "products_slugs" "ps"
+		INNER JOIN "products" "p" ON "p"."id" = "ps"."product_id"
+	WHERE "ps"."slug" = $1
+	ORDER BY "moved"
+	LIMIT 1;`
+
+	res := new(products.SlugRes)
+	if err := r.db.Get(res, query, slug); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product not found")
+		}
+		return nil, fmt.Errorf("failed to get product slug: %w", err)
+	}
+	return res, nil
+}
+
+// FindSlugs lists the current and old slugs of the other products that are
+// the slug or the slug with a number suffix
+func (r *productsRepository) FindSlugs(slug, productId string) ([]string, error) {
+	query := `
+	SELECT
+		"slug"
+	FROM "products"
+	WHERE ("slug" = $1 OR "slug" LIKE $1 || '-%')
+	AND "id" != $2
+	UNION
+	SELECT
+		"slug"
+	FROM "products_slugs"
+	WHERE ("slug" = $1 OR "slug" LIKE $1 || '-%')
+	AND "product_id" != $2;`
+
+	slugs := make([]string, 0)
+	if err := r.db.Select(&slugs, query, slug, productId); err != nil {
+		return nil, fmt.Errorf("failed to get slugs: %w", err)
+	}
+	return slugs, nil
+}
+
+func (r *productsRepository) FindCategories() ([]*appinfo.Category, error) {
+	query := `
+	SELECT
+		"id",
+		"title",
+		"slug"
+	FROM "categories";`
+
+	categories := make([]*appinfo.Category, 0)
+	if err := r.db.Select(&categories, query); err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	return categories, nil
+}
+
 func (r *productsRepository) InsertProduct(req *products.Product) (*products.Product, error) {
 	builder := productsPatterns.InsertProductBuilder(r.db, req)
 	product_id, err := productsPatterns.InsertProductEngineer(builder).InsertProduct()
@@ -132,10 +341,589 @@ func (r *productsRepository) UpdateProduct(req *products.Product) (*products.Pro
 	return product, nil
 }
 
+// DeleteProduct archives the product, orders keep their snapshot and the
+// images stay until the product is purged
 func (r *productsRepository) DeleteProduct(productId string) error {
+	query := `UPDATE "products" SET "status" = 'archived' WHERE "id" = $1 AND "status" != 'archived';`
+	result, err := r.db.ExecContext(context.Background(), query, productId)
+	if err != nil {
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("product not found or it is already archived")
+	}
+	return nil
+}
+
+func (r *productsRepository) PurgeProduct(productId string) error {
 	query := `DELETE FROM "products" WHERE "id" = $1;`
 	if _, err := r.db.ExecContext(context.Background(), query, productId); err != nil {
-		return fmt.Errorf("failed to delete product: %w", err)
+		return fmt.Errorf("failed to purge product: %w", err)
+	}
+	return nil
+}
+
+func (r *productsRepository) RestoreProduct(productId string) error {
+	query := `UPDATE "products" SET "status" = 'active' WHERE "id" = $1 AND "status" = 'archived';`
+	result, err := r.db.ExecContext(context.Background(), query, productId)
+	if err != nil {
+		return fmt.Errorf("failed to restore product: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("product not found or it is not archived")
+	}
+	return nil
+}
+
+// RotatePreviewToken gives the product a new preview token, links with the old one stop working
+func (r *productsRepository) RotatePreviewToken(productId string) (string, error) {
+	query := `UPDATE "products" SET "preview_token" = uuid_generate_v4() WHERE "id" = $1 RETURNING "preview_token"::TEXT;`
+
+	var token string
+	if err := r.db.Get(&token, query, productId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("product not found")
+		}
+		return "", fmt.Errorf("failed to create preview token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *productsRepository) RevokePreviewToken(productId string) error {
+	query := `UPDATE "products" SET "preview_token" = NULL WHERE "id" = $1;`
+	result, err := r.db.ExecContext(context.Background(), query, productId)
+	if err != nil {
+		return fmt.Errorf("failed to revoke preview token: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("product not found")
+	}
+	return nil
+}
+
+func (r *productsRepository) AddCategory(productId string, categoryId int) error {
+	query := `
+	INSERT INTO "products_categories" (
+		"product_id",
+		"category_id"
+	)
+	VALUES ($1, $2)
+	ON CONFLICT ("product_id", "category_id") DO NOTHING;`
+
+	if _, err := r.db.ExecContext(context.Background(), query, productId, categoryId); err != nil {
+		return fmt.Errorf("failed to add category: %w", err)
+	}
+	return nil
+}
+
+// RemoveCategory keeps at least one category on the product, the product row
+// is locked so two removals can't take the last two together.
+func (r *productsRepository) RemoveCategory(productId string, categoryId int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `SELECT "id" FROM "products" WHERE "id" = $1 FOR UPDATE;`, productId); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to remove category: %w", err)
+	}
+
+	query := `
+	DELETE FROM "products_categories"
+	WHERE "product_id" = $1
+	AND "category_id" = $2
+	AND EXISTS (
+		SELECT 1
+		FROM "products_categories" "pc"
+		WHERE "pc"."product_id" = $1
+		AND "pc"."category_id" != $2
+	);`
+
+	result, err := tx.ExecContext(ctx, query, productId, categoryId)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to remove category: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		tx.Rollback()
+		return fmt.Errorf("category not found or it is the last category of the product")
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *productsRepository) FindStock(productId string) ([]*products.StockAdjustment, error) {
+	query := `
+	SELECT
+		"id",
+		"product_id",
+		COALESCE("variant_id"::TEXT, '') AS "variant_id",
+		"qty",
+		"reason",
+		COALESCE("order_id", '') AS "order_id",
+		COALESCE("user_id", '') AS "user_id",
+		"created_at"
+	FROM "products_stocks"
+	WHERE "product_id" = $1
+	ORDER BY "created_at" DESC;`
+
+	stocks := make([]*products.StockAdjustment, 0)
+	if err := r.db.Select(&stocks, query, productId); err != nil {
+		return nil, fmt.Errorf("failed to get stock: %w", err)
+	}
+	return stocks, nil
+}
+
+// AdjustStock applies a manual stock movement, the stock can't go below zero.
+// It starts tracking the stock of a product that isn't tracked yet.
+func (r *productsRepository) AdjustStock(req *products.StockAdjustment) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// The stock of a product with variants is the sum of its variants
+	queryUpdate := `
+	UPDATE "products" SET
+		"stock" = COALESCE("stock", 0) + $1
+	WHERE "id" = $2
+	AND COALESCE("stock", 0) + $1 >= 0
+	AND NOT EXISTS (
+		SELECT 1
+		FROM "products_variants" "v"
+		WHERE "v"."product_id" = "products"."id"
+		AND "v"."deleted_at" IS NULL
+	);`
+	values := []any{req.Qty, req.ProductId}
+
+	if req.VariantId != "" {
+		queryUpdate = `
+		UPDATE "products_variants" SET
+			"stock" = "stock" + $1
+		WHERE "product_id" = $2
+		AND "id" = $3
+		AND "deleted_at" IS NULL
+		AND "stock" + $1 >= 0;`
+		values = append(values, req.VariantId)
+	}
+
+	result, err := tx.ExecContext(ctx, queryUpdate, values...)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to adjust stock: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		tx.Rollback()
+		return fmt.Errorf("product not found, variant is required or stock is not enough")
+	}
+
+	queryInsert := `
+	INSERT INTO "products_stocks" (
+		"product_id",
+		"variant_id",
+		"qty",
+		"reason",
+		"user_id"
+	)
+	VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5)
+	RETURNING "id", "created_at";`
+
+	if err := tx.QueryRowxContext(
+		ctx,
+		queryInsert,
+		req.ProductId,
+		req.VariantId,
+		req.Qty,
+		req.Reason,
+		req.UserId,
+	).Scan(&req.Id, &req.CreatedAt); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to insert stock: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *productsRepository) FindPrice(productId string) ([]*products.Price, error) {
+	query := `
+	SELECT
+		"id",
+		"product_id",
+		"price",
+		"compare_at_price",
+		"effective_from",
+		"effective_to",
+		COALESCE("user_id", '') AS "user_id",
+		"created_at"
+	FROM "products_prices"
+	WHERE "product_id" = $1
+	ORDER BY "effective_from" DESC;`
+
+	prices := make([]*products.Price, 0)
+	if err := r.db.Select(&prices, query, productId); err != nil {
+		return nil, fmt.Errorf("failed to get prices: %w", err)
+	}
+	return prices, nil
+}
+
+// SchedulePrice puts a price in effect from req.EffectiveFrom, changes that were
+// scheduled inside the new range are replaced. A price that starts now is set
+// on the product right away, later ones by ApplyScheduledPrice.
+func (r *productsRepository) SchedulePrice(req *products.PriceReq) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var productId string
+	if err := tx.GetContext(ctx, &productId, `SELECT "id" FROM "products" WHERE "id" = $1 FOR UPDATE;`, req.ProductId); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("product not found")
+		}
+		return fmt.Errorf("failed to schedule price: %w", err)
+	}
+
+	var window struct {
+		From  string  `db:"from"`
+		To    *string `db:"to"`
+		IsNow bool    `db:"is_now"`
+	}
+	query := `
+	SELECT
+		"t"."from"::TEXT AS "from",
+		"t"."to"::TEXT AS "to",
+		("t"."from" <= now()) AS "is_now"
+	FROM (
+		SELECT
+			COALESCE((NULLIF($1, '')::TIMESTAMPTZ)::TIMESTAMP, now()::TIMESTAMP) AS "from",
+			(NULLIF($2, '')::TIMESTAMPTZ)::TIMESTAMP AS "to"
+	) AS "t";`
+	if err := tx.GetContext(ctx, &window, query, req.EffectiveFrom, req.EffectiveTo); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to schedule price: %w", err)
+	}
+
+	// The price in effect at the end of the range comes back then
+	var revert *products.Price
+	if window.To != nil {
+		query := `
+		DELETE FROM "products_prices"
+		WHERE "product_id" = $1
+		AND "effective_from" > $2::TIMESTAMP
+		AND "effective_from" < $3::TIMESTAMP;`
+		if _, err := tx.ExecContext(ctx, query, req.ProductId, window.From, *window.To); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to schedule price: %w", err)
+		}
+
+		query = `
+		SELECT
+			"price",
+			"compare_at_price"
+		FROM "products_prices"
+		WHERE "product_id" = $1
+		AND "effective_from" <= $2::TIMESTAMP
+		ORDER BY "effective_from" DESC
+		LIMIT 1;`
+		revert = new(products.Price)
+		if err := tx.GetContext(ctx, revert, query, req.ProductId, *window.To); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				tx.Rollback()
+				return fmt.Errorf("failed to schedule price: %w", err)
+			}
+			revert = nil
+		}
+	}
+
+	query = `
+	INSERT INTO "products_prices" (
+		"product_id",
+		"price",
+		"compare_at_price",
+		"effective_from",
+		"user_id"
+	)
+	VALUES ($1, $2, $3, $4::TIMESTAMP, NULLIF($5, ''))
+	ON CONFLICT ("product_id", "effective_from") DO UPDATE
+	SET "price" = EXCLUDED."price",
+		"compare_at_price" = EXCLUDED."compare_at_price",
+		"user_id" = EXCLUDED."user_id";`
+	if _, err := tx.ExecContext(ctx, query, req.ProductId, req.Price, req.CompareAtPrice, window.From, req.UserId); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to schedule price: %w", err)
+	}
+
+	if revert != nil {
+		query := `
+		INSERT INTO "products_prices" (
+			"product_id",
+			"price",
+			"compare_at_price",
+			"effective_from",
+			"user_id"
+		)
+		VALUES ($1, $2, $3, $4::TIMESTAMP, NULLIF($5, ''))
+		ON CONFLICT ("product_id", "effective_from") DO NOTHING;`
+		if _, err := tx.ExecContext(ctx, query, req.ProductId, revert.Price, revert.CompareAtPrice, *window.To, req.UserId); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to schedule price: %w", err)
+		}
+	}
+
+	if window.IsNow {
+		if _, err := tx.ExecContext(ctx, `UPDATE "products" SET "price" = $2 WHERE "id" = $1;`, req.ProductId, req.Price); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to schedule price: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// ApplyScheduledPrice sets the prices that have come into effect on their
+// products, it returns how many products changed
+func (r *productsRepository) ApplyScheduledPrice() (int, error) {
+	query := `
+	UPDATE "products" "p" SET
+		"price" = "pp"."price"
+	FROM (
+		SELECT DISTINCT ON ("product_id")
+			"product_id",
+			"price"
+		FROM "products_prices"
+		WHERE "effective_from" <= now()
+		ORDER BY "product_id", "effective_from" DESC
+	) AS "pp"
+	WHERE "pp"."product_id" = "p"."id"
+	AND "p"."price" != "pp"."price";`
+
+	result, err := r.db.ExecContext(context.Background(), query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply prices: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return int(rows), nil
+}
+
+// lockImages locks the product so two edits can't mix up the order of its
+// images, it returns the ids of the images in order
+func lockImages(ctx context.Context, tx *sqlx.Tx, productId string) ([]string, error) {
+	var id string
+	if err := tx.GetContext(ctx, &id, `SELECT "id" FROM "products" WHERE "id" = $1 FOR UPDATE;`, productId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product not found")
+		}
+		return nil, fmt.Errorf("failed to get images: %w", err)
+	}
+
+	query := `
+	SELECT
+		"id"::TEXT
+	FROM "images"
+	WHERE "product_id" = $1
+	ORDER BY "position", "created_at";`
+
+	imageIds := make([]string, 0)
+	if err := tx.SelectContext(ctx, &imageIds, query, productId); err != nil {
+		return nil, fmt.Errorf("failed to get images: %w", err)
+	}
+	return imageIds, nil
+}
+
+// orderImages numbers the images of the product in the order of the ids
+func orderImages(ctx context.Context, tx *sqlx.Tx, productId string, imageIds []string) error {
+	query := `
+	UPDATE "images" SET
+		"position" = array_position($2::VARCHAR[], "id"::TEXT) - 1
+	WHERE "product_id" = $1;`
+
+	if _, err := tx.ExecContext(ctx, query, productId, imageIds); err != nil {
+		return fmt.Errorf("failed to order images: %w", err)
+	}
+	return nil
+}
+
+// AddImage adds the image after the others, or first when it is primary
+func (r *productsRepository) AddImage(req *products.ImageReq) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	imageIds, err := lockImages(ctx, tx, req.ProductId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var alt string
+	if req.Alt != nil {
+		alt = *req.Alt
+	}
+
+	query := `
+	INSERT INTO "images" (
+		"filename",
+		"url",
+		"alt",
+		"position",
+		"product_id"
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING "id"::TEXT;`
+
+	var imageId string
+	if err := tx.GetContext(ctx, &imageId, query, req.Filename, req.Url, alt, len(imageIds), req.ProductId); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to insert image: %w", err)
+	}
+
+	if req.IsPrimary {
+		if err := orderImages(ctx, tx, req.ProductId, append([]string{imageId}, imageIds...)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// UpdateImage sets the alt text, a primary image is moved first and the
+// others keep their order
+func (r *productsRepository) UpdateImage(req *products.ImageReq) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	imageIds, err := lockImages(ctx, tx, req.ProductId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	index := slices.Index(imageIds, req.ImageId)
+	if index == -1 {
+		tx.Rollback()
+		return fmt.Errorf("image not found")
+	}
+
+	if req.Alt != nil {
+		if _, err := tx.ExecContext(ctx, `UPDATE "images" SET "alt" = $2 WHERE "id"::TEXT = $1;`, req.ImageId, *req.Alt); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update image: %w", err)
+		}
+	}
+
+	if req.IsPrimary && index != 0 {
+		imageIds = slices.Delete(imageIds, index, index+1)
+		if err := orderImages(ctx, tx, req.ProductId, append([]string{req.ImageId}, imageIds...)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// ReorderImage orders the images as the ids are, every image of the product
+// has to be listed once
+func (r *productsRepository) ReorderImage(productId string, imageIds []string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	oldImageIds, err := lockImages(ctx, tx, productId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	sorted := slices.Sorted(slices.Values(imageIds))
+	slices.Sort(oldImageIds)
+	if !slices.Equal(sorted, oldImageIds) {
+		tx.Rollback()
+		return fmt.Errorf("image ids must list every image of the product once")
+	}
+
+	if err := orderImages(ctx, tx, productId, imageIds); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// RemoveImage deletes the image, its file is deleted too unless another image
+// still points to it
+func (r *productsRepository) RemoveImage(productId, imageId string) error {
+	query := `
+	DELETE FROM "images" "i"
+	WHERE "i"."product_id" = $1
+	AND "i"."id"::TEXT = $2
+	RETURNING
+		"i"."filename",
+		EXISTS (
+			SELECT 1
+			FROM "images" "o"
+			WHERE "o"."url" = "i"."url"
+			AND "o"."id" != "i"."id"
+		) AS "shared";`
+
+	image := struct {
+		Filename string `db:"filename"`
+		Shared   bool   `db:"shared"`
+	}{}
+	if err := r.db.Get(&image, query, productId, imageId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("image not found")
+		}
+		return fmt.Errorf("failed to remove image: %w", err)
+	}
+
+	if !image.Shared {
+		r.filesUsecases.DeleteFile([]*files.DeleteFileReq{
+			{Destination: fmt.Sprintf("images/products/%s", image.Filename)},
+		})
 	}
 	return nil
 }
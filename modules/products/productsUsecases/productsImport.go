@@ -0,0 +1,486 @@
+package productsUsecases
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/files"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
+	"github.com/IzePhanthakarn/go-basic-shop/pkg/utils"
+)
+
+// importRow is a valid row that is waiting to be written
+type importRow struct {
+	line     int
+	product  *products.Product
+	isUpdate bool
+	zipFiles map[int]*zip.File // index in product.Images -> image from the zip
+}
+
+var imageExtensions = map[string]bool{
+	"png":  true,
+	"jpg":  true,
+	"jpeg": true,
+}
+
+// ImportProduct validates every row first, nothing is written when a row is
+// invalid or on a dry run. Valid files are written row by row, a row that
+// fails then is reported and the rows before it are kept.
+func (u *productsUsecase) ImportProduct(req *products.ImportReq) (*products.ImportRes, error) {
+	header, records, err := readImportCsv(req.File)
+	if err != nil {
+		return nil, err
+	}
+
+	zipFiles, err := readImportZip(req.Images)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := u.productsRepository.FindCategories()
+	if err != nil {
+		return nil, err
+	}
+	// A title may be shared by many categories, only the slug is unique
+	categoryBySlug := make(map[string]int)
+	categoryByTitle := make(map[string][]int)
+	for _, c := range categories {
+		categoryBySlug[c.Slug] = c.Id
+		title := strings.ToLower(c.Title)
+		categoryByTitle[title] = append(categoryByTitle[title], c.Id)
+	}
+
+	ids, skus := make([]string, 0), make([]string, 0)
+	for _, record := range records {
+		if id := importCell(header, record, "id"); id != "" {
+			ids = append(ids, id)
+		}
+		if sku := importCell(header, record, "sku"); sku != "" {
+			skus = append(skus, sku)
+		}
+	}
+	keys, err := u.productsRepository.FindProductKeys(ids, skus)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	idBySku := make(map[string]string)
+	for _, k := range keys {
+		existing[k.Id] = true
+		if k.Sku != "" {
+			idBySku[k.Sku] = k.Id
+		}
+	}
+
+	res := &products.ImportRes{
+		DryRun: req.DryRun,
+		Total:  len(records),
+		Rows:   make([]*products.ImportRowRes, 0),
+		Errors: make([]*products.ImportRowErr, 0),
+	}
+
+	rows := make([]*importRow, 0)
+	seen := make(map[string]int)
+	for i, record := range records {
+		line := i + 2
+		rowErr := func(column, msg string, args ...any) {
+			res.Errors = append(res.Errors, &products.ImportRowErr{
+				Row:    line,
+				Column: column,
+				Msg:    fmt.Sprintf(msg, args...),
+			})
+		}
+		errCount := len(res.Errors)
+
+		row := &importRow{
+			line: line,
+			product: &products.Product{
+				Id:          importCell(header, record, "id"),
+				Sku:         importCell(header, record, "sku"),
+				Title:       importCell(header, record, "title"),
+				Description: importCell(header, record, "description"),
+				Status:      importCell(header, record, "status"),
+			},
+			zipFiles: make(map[int]*zip.File),
+		}
+		p := row.product
+
+		// Match by id, then by sku
+		switch {
+		case p.Id != "":
+			if !existing[p.Id] {
+				rowErr("id", "product %s not found", p.Id)
+			}
+			if owner, ok := idBySku[p.Sku]; ok && p.Sku != "" && owner != p.Id {
+				rowErr("sku", "sku %s belongs to product %s", p.Sku, owner)
+			}
+			row.isUpdate = true
+		case p.Sku != "":
+			if id, ok := idBySku[p.Sku]; ok {
+				p.Id = id
+				row.isUpdate = true
+			}
+		}
+
+		key := "id:" + p.Id
+		if !row.isUpdate {
+			key = "sku:" + p.Sku
+		}
+		if key != "sku:" {
+			if first, ok := seen[key]; ok {
+				rowErr("", "duplicate of row %d", first)
+			}
+			seen[key] = line
+		}
+
+		if !row.isUpdate && p.Title == "" {
+			rowErr("title", "title is required")
+		}
+
+		if price := importCell(header, record, "price"); price != "" {
+			value, err := strconv.ParseFloat(price, 64)
+			if err != nil || value < 0 {
+				rowErr("price", "price must be a number not less than 0")
+			}
+			p.Price = value
+		} else if !row.isUpdate {
+			rowErr("price", "price is required")
+		}
+
+		if p.Status != "" && !products.Statuses[p.Status] {
+			rowErr("status", "status is invalid")
+		} else if p.Status == "archived" && !row.isUpdate {
+			rowErr("status", "a new product can not be archived")
+		}
+
+		for _, key := range importList(importCell(header, record, "categories")) {
+			categoryId, ok := categoryBySlug[key]
+			if !ok {
+				switch ids := categoryByTitle[strings.ToLower(key)]; len(ids) {
+				case 0:
+					rowErr("categories", "category %q not found", key)
+					continue
+				case 1:
+					categoryId = ids[0]
+				default:
+					rowErr("categories", "category %q matches %d categories, use its slug", key, len(ids))
+					continue
+				}
+			}
+			p.Categories = append(p.Categories, &appinfo.Category{Id: categoryId})
+		}
+		if !row.isUpdate && len(p.Categories) == 0 {
+			rowErr("categories", "category is required")
+		}
+
+		for _, image := range importList(importCell(header, record, "images")) {
+			if strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+				p.Images = append(p.Images, &entities.Image{
+					Filename: path.Base(image),
+					Url:      image,
+				})
+				continue
+			}
+
+			file, ok := zipFiles[image]
+			if !ok {
+				rowErr("images", "image %s is not in the zip", image)
+				continue
+			}
+			if !imageExtensions[strings.ToLower(strings.TrimPrefix(filepath.Ext(image), "."))] {
+				rowErr("images", "image %s has an invalid file extension", image)
+				continue
+			}
+			if req.ImageLimit > 0 && file.UncompressedSize64 > uint64(req.ImageLimit) {
+				rowErr("images", "image %s is too large", image)
+				continue
+			}
+			row.zipFiles[len(p.Images)] = file
+			p.Images = append(p.Images, &entities.Image{})
+		}
+
+		if len(res.Errors) > errCount {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if len(res.Errors) > 0 || req.DryRun {
+		for _, row := range rows {
+			res.Rows = append(res.Rows, importRowRes(row))
+		}
+		return res, nil
+	}
+
+	for _, row := range rows {
+		if err := u.uploadImportImages(row); err != nil {
+			res.Errors = append(res.Errors, &products.ImportRowErr{Row: row.line, Column: "images", Msg: err.Error()})
+			continue
+		}
+
+		if row.isUpdate {
+			if _, err := u.UpdateProduct(row.product); err != nil {
+				u.removeImportImages(row)
+				res.Errors = append(res.Errors, &products.ImportRowErr{Row: row.line, Msg: err.Error()})
+				continue
+			}
+			res.Updated++
+		} else {
+			product, err := u.AddProduct(row.product)
+			if err != nil {
+				u.removeImportImages(row)
+				res.Errors = append(res.Errors, &products.ImportRowErr{Row: row.line, Msg: err.Error()})
+				continue
+			}
+			row.product.Id = product.Id
+			res.Inserted++
+		}
+		res.Rows = append(res.Rows, importRowRes(row))
+	}
+	return res, nil
+}
+
+// ExportProduct writes the products of the filter in the import format
+func (u *productsUsecase) ExportProduct(req *products.ProductFilter, w io.Writer) error {
+	allCategories, err := u.productsRepository.FindCategories()
+	if err != nil {
+		return err
+	}
+	categorySlug := make(map[int]string)
+	for _, c := range allCategories {
+		categorySlug[c.Id] = c.Slug
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(products.ImportColumns); err != nil {
+		return err
+	}
+
+	req.Page = 1
+	for {
+		list, _ := u.productsRepository.FindProduct(req)
+		for _, p := range list {
+			categories := make([]string, 0)
+			for _, c := range p.Categories {
+				categories = append(categories, categorySlug[c.Id])
+			}
+			images := make([]string, 0)
+			for _, img := range p.Images {
+				images = append(images, img.Url)
+			}
+
+			if err := writer.Write([]string{
+				p.Id,
+				p.Sku,
+				p.Title,
+				p.Description,
+				strconv.FormatFloat(p.Price, 'f', -1, 64),
+				p.Status,
+				strings.Join(categories, products.ImportSeparator),
+				strings.Join(images, products.ImportSeparator),
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(list) < req.Limit {
+			break
+		}
+		req.Page++
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (u *productsUsecase) uploadImportImages(row *importRow) error {
+	if len(row.zipFiles) == 0 {
+		return nil
+	}
+
+	indexes := make([]int, 0)
+	req := make([]*files.FileReq, 0)
+	for i, file := range row.zipFiles {
+		content, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read image %s: %w", file.Name, err)
+		}
+		data, err := io.ReadAll(content)
+		content.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read image %s: %w", file.Name, err)
+		}
+
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(file.Name), "."))
+		filename := utils.RandFileName(ext)
+		indexes = append(indexes, i)
+		req = append(req, &files.FileReq{
+			Destination: "images/products/" + filename,
+			Extension:   ext,
+			FileName:    filename,
+			Data:        data,
+		})
+	}
+
+	uploaded, err := u.filesUsecase.UploadToGCP(req)
+	if err != nil {
+		return err
+	}
+
+	// Uploads finish in any order, they are matched back by file name
+	urls := make(map[string]string)
+	for _, f := range uploaded {
+		urls[f.FileName] = f.Url
+	}
+	for i, index := range indexes {
+		row.product.Images[index].Filename = req[i].FileName
+		row.product.Images[index].Url = urls[req[i].FileName]
+	}
+	return nil
+}
+
+// removeImportImages deletes the images uploaded for a row whose product
+// couldn't be written, so they don't stay in the bucket
+func (u *productsUsecase) removeImportImages(row *importRow) {
+	req := make([]*files.DeleteFileReq, 0)
+	for index := range row.zipFiles {
+		if filename := row.product.Images[index].Filename; filename != "" {
+			req = append(req, &files.DeleteFileReq{
+				Destination: fmt.Sprintf("images/products/%s", filename),
+			})
+		}
+	}
+	if len(req) == 0 {
+		return
+	}
+
+	if err := u.filesUsecase.DeleteFile(req); err != nil {
+		log.Printf("failed to delete images of import row %d: %v", row.line, err)
+	}
+}
+
+func importRowRes(row *importRow) *products.ImportRowRes {
+	action := "insert"
+	if row.isUpdate {
+		action = "update"
+	}
+	return &products.ImportRowRes{
+		Row:    row.line,
+		Id:     row.product.Id,
+		Action: action,
+	}
+}
+
+// readImportCsv reads the CSV, the header maps the column names to their index
+func readImportCsv(file *multipart.FileHeader) (map[string]int, [][]string, error) {
+	if file == nil {
+		return nil, nil, fmt.Errorf("csv file is required")
+	}
+	content, err := file.Open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	defer content.Close()
+
+	reader := csv.NewReader(content)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("csv file is empty")
+	}
+	if len(records)-1 > products.ImportLimit {
+		return nil, nil, fmt.Errorf("csv file must have at most %d rows", products.ImportLimit)
+	}
+
+	columns := make(map[string]bool)
+	for _, c := range products.ImportColumns {
+		columns[c] = true
+	}
+
+	header := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !columns[name] {
+			return nil, nil, fmt.Errorf("unknown column %q", name)
+		}
+		if _, ok := header[name]; ok {
+			return nil, nil, fmt.Errorf("duplicate column %q", name)
+		}
+		header[name] = i
+	}
+	if _, ok := header["title"]; !ok {
+		if _, ok := header["id"]; !ok {
+			if _, ok := header["sku"]; !ok {
+				return nil, nil, fmt.Errorf("csv needs an id, sku or title column")
+			}
+		}
+	}
+	return header, records[1:], nil
+}
+
+// readImportZip indexes the files of the zip by their name
+func readImportZip(file *multipart.FileHeader) (map[string]*zip.File, error) {
+	zipFiles := make(map[string]*zip.File)
+	if file == nil {
+		return zipFiles, nil
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip: %w", err)
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip: %w", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip: %w", err)
+	}
+
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		zipFiles[f.Name] = f
+		// Images can be named with or without their folder
+		if _, ok := zipFiles[path.Base(f.Name)]; !ok {
+			zipFiles[path.Base(f.Name)] = f
+		}
+	}
+	return zipFiles, nil
+}
+
+func importCell(header map[string]int, record []string, column string) string {
+	i, ok := header[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func importList(cell string) []string {
+	list := make([]string, 0)
+	for _, v := range strings.Split(cell, products.ImportSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
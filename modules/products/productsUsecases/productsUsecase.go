@@ -1,28 +1,59 @@
 package productsUsecases
 
 import (
+	"fmt"
+	"io"
+	"log"
 	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/files/filesUsecases"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/pkg/utils"
 )
 
 type IProductsUsecase interface {
 	FindOneProduct(productId string) (*products.Product, error)
+	FindProductSlug(slug string) (*products.SlugRes, error)
 	FindProduct(req *products.ProductFilter) *entities.PaginateRes
+	FindProductCursor(req *products.ProductFilter) *entities.CursorRes
+	FindFacet(req *products.ProductFilter) *products.Facets
 	AddProduct(req *products.Product) (*products.Product, error)
 	UpdateProduct(req *products.Product) (*products.Product, error)
 	DeleteProduct(productId string) error
+	PurgeProduct(productId string) error
+	RestoreProduct(productId string) (*products.Product, error)
+	RotatePreviewToken(productId string) (*products.PreviewTokenRes, error)
+	RevokePreviewToken(productId string) error
+	AddCategory(productId string, categoryId int) (*products.Product, error)
+	RemoveCategory(productId string, categoryId int) (*products.Product, error)
+	AddImage(req *products.ImageReq) (*products.Product, error)
+	UpdateImage(req *products.ImageReq) (*products.Product, error)
+	ReorderImage(productId string, imageIds []string) (*products.Product, error)
+	RemoveImage(productId, imageId string) (*products.Product, error)
+	FindStock(productId string) ([]*products.StockAdjustment, error)
+	AdjustStock(req *products.StockAdjustment) error
+	ImportProduct(req *products.ImportReq) (*products.ImportRes, error)
+	ExportProduct(req *products.ProductFilter, w io.Writer) error
+	FindPrice(productId string) ([]*products.Price, error)
+	SchedulePrice(req *products.PriceReq) ([]*products.Price, error)
+	ApplyScheduledPrice() error
 }
 
 type productsUsecase struct {
 	productsRepository productsRepositories.IProductsRepository
+	filesUsecase       filesUsecases.IFilesUsecase
 }
 
-func ProductsUsecase(productsRepository productsRepositories.IProductsRepository) IProductsUsecase {
+func ProductsUsecase(productsRepository productsRepositories.IProductsRepository, filesUsecase filesUsecases.IFilesUsecase) IProductsUsecase {
 	return &productsUsecase{
 		productsRepository: productsRepository,
+		filesUsecase:       filesUsecase,
 	}
 }
 
@@ -34,6 +65,10 @@ func (u *productsUsecase) FindOneProduct(productId string) (*products.Product, e
 	return products, nil
 }
 
+func (u *productsUsecase) FindProductSlug(slug string) (*products.SlugRes, error) {
+	return u.productsRepository.FindProductSlug(slug)
+}
+
 func (u *productsUsecase) FindProduct(req *products.ProductFilter) *entities.PaginateRes {
 	products, count := u.productsRepository.FindProduct(req)
 	return &entities.PaginateRes{
@@ -45,7 +80,49 @@ func (u *productsUsecase) FindProduct(req *products.ProductFilter) *entities.Pag
 	}
 }
 
+func (u *productsUsecase) FindProductCursor(req *products.ProductFilter) *entities.CursorRes {
+	productsData, count := u.productsRepository.FindProductCursor(req)
+
+	res := entities.NewCursorRes(req.CursorReq, req.OrderBy, req.SortBy, req.Limit, productsData, func(p *products.Product) (string, string) {
+		switch req.OrderBy {
+		case "title":
+			return p.Title, p.Id
+		case "price":
+			return strconv.FormatFloat(p.Price, 'f', -1, 64), p.Id
+		case "rating":
+			return strconv.FormatFloat(p.Rating, 'f', -1, 64), p.Id
+		case "created_at":
+			return p.CreatedAt, p.Id
+		}
+		return p.Id, p.Id
+	})
+	if req.WithTotal {
+		res.TotalItem = &count
+	}
+	return res
+}
+
+func (u *productsUsecase) FindFacet(req *products.ProductFilter) *products.Facets {
+	return u.productsRepository.FindFacet(req)
+}
+
 func (u *productsUsecase) AddProduct(req *products.Product) (*products.Product, error) {
+	if req.Status == "archived" {
+		return nil, fmt.Errorf("a new product can not be archived")
+	}
+	if req.Status != "" && !products.Statuses[req.Status] {
+		return nil, fmt.Errorf("status is invalid")
+	}
+	if err := checkPublishWindow(req); err != nil {
+		return nil, err
+	}
+	if err := checkVariants(req.Options, req.Variants); err != nil {
+		return nil, err
+	}
+	if err := u.setSlug(req); err != nil {
+		return nil, err
+	}
+
 	product, err := u.productsRepository.InsertProduct(req)
 	if err != nil {
 		return nil, err
@@ -54,6 +131,38 @@ func (u *productsUsecase) AddProduct(req *products.Product) (*products.Product,
 }
 
 func (u *productsUsecase) UpdateProduct(req *products.Product) (*products.Product, error) {
+	if req.Status != "" && !products.Statuses[req.Status] {
+		return nil, fmt.Errorf("status is invalid")
+	}
+	if err := checkPublishWindow(req); err != nil {
+		return nil, err
+	}
+	if req.Slug != "" {
+		if err := u.setSlug(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Options != nil || req.Variants != nil {
+		options, variants := req.Options, req.Variants
+		if options == nil || variants == nil {
+			product, err := u.productsRepository.FindOneProduct(req.Id)
+			if err != nil {
+				return nil, err
+			}
+			if options == nil {
+				options = product.Options
+			}
+			if variants == nil {
+				variants = product.Variants
+			}
+		}
+
+		if err := checkVariants(options, variants); err != nil {
+			return nil, err
+		}
+	}
+
 	product, err := u.productsRepository.UpdateProduct(req)
 	if err != nil {
 		return nil, err
@@ -61,9 +170,283 @@ func (u *productsUsecase) UpdateProduct(req *products.Product) (*products.Produc
 	return product, nil
 }
 
+// setSlug slugifies the slug an admin sent, which must be free, or generates
+// one from the title with a number suffix when it is taken. Old slugs of other
+// products are taken too so their links keep working.
+func (u *productsUsecase) setSlug(req *products.Product) error {
+	override := req.Slug != ""
+
+	slug := req.Slug
+	if !override {
+		slug = req.Title
+	}
+	slug = utils.Slugify(slug)
+	if slug == "" {
+		if override {
+			return fmt.Errorf("slug is invalid")
+		}
+		slug = "product"
+	}
+
+	taken, err := u.productsRepository.FindSlugs(slug, req.Id)
+	if err != nil {
+		return err
+	}
+	if override && slices.Contains(taken, slug) {
+		return fmt.Errorf("slug %s is already taken", slug)
+	}
+
+	req.Slug = utils.UniqueSlug(slug, taken)
+	return nil
+}
+
 func (u *productsUsecase) DeleteProduct(productId string) error {
 	if err := u.productsRepository.DeleteProduct(productId); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (u *productsUsecase) PurgeProduct(productId string) error {
+	if err := u.productsRepository.PurgeProduct(productId); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *productsUsecase) RestoreProduct(productId string) (*products.Product, error) {
+	if err := u.productsRepository.RestoreProduct(productId); err != nil {
+		return nil, err
+	}
+	return u.productsRepository.FindOneProduct(productId)
+}
+
+func (u *productsUsecase) RotatePreviewToken(productId string) (*products.PreviewTokenRes, error) {
+	token, err := u.productsRepository.RotatePreviewToken(productId)
+	if err != nil {
+		return nil, err
+	}
+	return &products.PreviewTokenRes{PreviewToken: token}, nil
+}
+
+func (u *productsUsecase) RevokePreviewToken(productId string) error {
+	return u.productsRepository.RevokePreviewToken(productId)
+}
+
+func (u *productsUsecase) AddCategory(productId string, categoryId int) (*products.Product, error) {
+	if err := u.productsRepository.AddCategory(productId, categoryId); err != nil {
+		return nil, err
+	}
+	return u.productsRepository.FindOneProduct(productId)
+}
+
+func (u *productsUsecase) RemoveCategory(productId string, categoryId int) (*products.Product, error) {
+	if err := u.productsRepository.RemoveCategory(productId, categoryId); err != nil {
+		return nil, err
+	}
+	return u.productsRepository.FindOneProduct(productId)
+}
+
+func (u *productsUsecase) AddImage(req *products.ImageReq) (*products.Product, error) {
+	if req.Filename == "" || req.Url == "" {
+		return nil, fmt.Errorf("filename and url are required")
+	}
+	if req.Alt != nil {
+		alt := strings.TrimSpace(*req.Alt)
+		req.Alt = &alt
+	}
+
+	if err := u.productsRepository.AddImage(req); err != nil {
+		return nil, err
+	}
+	return u.productsRepository.FindOneProduct(req.ProductId)
+}
+
+func (u *productsUsecase) UpdateImage(req *products.ImageReq) (*products.Product, error) {
+	if req.Alt == nil && !req.IsPrimary {
+		return nil, fmt.Errorf("alt or is_primary is required")
+	}
+	if req.Alt != nil {
+		alt := strings.TrimSpace(*req.Alt)
+		req.Alt = &alt
+	}
+
+	if err := u.productsRepository.UpdateImage(req); err != nil {
+		return nil, err
+	}
+	return u.productsRepository.FindOneProduct(req.ProductId)
+}
+
+func (u *productsUsecase) ReorderImage(productId string, imageIds []string) (*products.Product, error) {
+	if len(imageIds) == 0 {
+		return nil, fmt.Errorf("image ids are required")
+	}
+
+	if err := u.productsRepository.ReorderImage(productId, imageIds); err != nil {
+		return nil, err
+	}
+	return u.productsRepository.FindOneProduct(productId)
+}
+
+func (u *productsUsecase) RemoveImage(productId, imageId string) (*products.Product, error) {
+	if err := u.productsRepository.RemoveImage(productId, imageId); err != nil {
+		return nil, err
+	}
+	return u.productsRepository.FindOneProduct(productId)
+}
+
+func (u *productsUsecase) FindStock(productId string) ([]*products.StockAdjustment, error) {
+	stocks, err := u.productsRepository.FindStock(productId)
+	if err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+func (u *productsUsecase) AdjustStock(req *products.StockAdjustment) error {
+	if req.Qty == 0 {
+		return fmt.Errorf("qty must not be zero")
+	}
+	if req.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+
+	if err := u.productsRepository.AdjustStock(req); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkVariants makes sure every variant picks one allowed value of every
+// option, and no two variants pick the same values.
+func checkVariants(options []*products.Option, variants []*products.Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	if len(options) == 0 {
+		return fmt.Errorf("options are required for variants")
+	}
+
+	allowed := make(map[string]map[string]bool)
+	for _, o := range options {
+		if o.Name == "" || len(o.Values) == 0 {
+			return fmt.Errorf("option name and values are required")
+		}
+		if allowed[o.Name] != nil {
+			return fmt.Errorf("option %s is duplicated", o.Name)
+		}
+		allowed[o.Name] = make(map[string]bool)
+		for _, v := range o.Values {
+			allowed[o.Name][v] = true
+		}
+	}
+
+	skus := make(map[string]bool)
+	combinations := make(map[string]bool)
+	for _, v := range variants {
+		if v.Sku == "" {
+			return fmt.Errorf("variant sku is required")
+		}
+		if skus[v.Sku] {
+			return fmt.Errorf("variant sku %s is duplicated", v.Sku)
+		}
+		skus[v.Sku] = true
+
+		if v.Price != nil && *v.Price < 0 {
+			return fmt.Errorf("variant %s price must not be negative", v.Sku)
+		}
+		if v.AvailableQty < 0 {
+			return fmt.Errorf("variant %s available qty must not be negative", v.Sku)
+		}
+		if len(v.Options) != len(options) {
+			return fmt.Errorf("variant %s must have a value for every option", v.Sku)
+		}
+
+		key := make([]string, 0, len(options))
+		for _, o := range options {
+			value, ok := v.Options[o.Name]
+			if !ok || !allowed[o.Name][value] {
+				return fmt.Errorf("variant %s has invalid %s", v.Sku, o.Name)
+			}
+			key = append(key, o.Name+"="+value)
+		}
+		if combinations[strings.Join(key, ",")] {
+			return fmt.Errorf("variant %s options are duplicated", v.Sku)
+		}
+		combinations[strings.Join(key, ",")] = true
+	}
+	return nil
+}
+
+func (u *productsUsecase) FindPrice(productId string) ([]*products.Price, error) {
+	prices, err := u.productsRepository.FindPrice(productId)
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (u *productsUsecase) SchedulePrice(req *products.PriceReq) ([]*products.Price, error) {
+	if req.Price < 0 || (req.CompareAtPrice != nil && *req.CompareAtPrice < 0) {
+		return nil, fmt.Errorf("price must not be negative")
+	}
+
+	from := time.Now()
+	if req.EffectiveFrom != "" {
+		t, err := time.Parse(time.RFC3339, req.EffectiveFrom)
+		if err != nil {
+			return nil, fmt.Errorf("effective_from must be an RFC3339 time")
+		}
+		// The history can't be rewritten, a minute is left for clock skew
+		if t.Before(from.Add(-time.Minute)) {
+			return nil, fmt.Errorf("effective_from must not be in the past")
+		}
+		from = t
+	}
+	if req.EffectiveTo != "" {
+		t, err := time.Parse(time.RFC3339, req.EffectiveTo)
+		if err != nil {
+			return nil, fmt.Errorf("effective_to must be an RFC3339 time")
+		}
+		if !t.After(from) {
+			return nil, fmt.Errorf("effective_to must be after effective_from")
+		}
+	}
+
+	if err := u.productsRepository.SchedulePrice(req); err != nil {
+		return nil, err
+	}
+	return u.productsRepository.FindPrice(req.ProductId)
+}
+
+func (u *productsUsecase) ApplyScheduledPrice() error {
+	count, err := u.productsRepository.ApplyScheduledPrice()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("%d scheduled prices applied", count)
+	}
+	return nil
+}
+
+// checkPublishWindow makes sure publish_at and unpublish_at are RFC3339 times
+// and, when both are sent, unpublish_at comes later
+func checkPublishWindow(req *products.Product) error {
+	times := make([]time.Time, 2)
+	for i, t := range []*string{req.PublishAt, req.UnpublishAt} {
+		if t == nil || *t == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, *t)
+		if err != nil {
+			return fmt.Errorf("publish_at and unpublish_at must be RFC3339 times")
+		}
+		times[i] = parsed
+	}
+
+	if !times[0].IsZero() && !times[1].IsZero() && !times[1].After(times[0]) {
+		return fmt.Errorf("unpublish_at must be after publish_at")
+	}
+	return nil
+}
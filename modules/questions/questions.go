@@ -0,0 +1,43 @@
+package questions
+
+import "github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+
+type QuestionFilter struct {
+	ProductId string `query:"-"`
+	Status    string `query:"status"` // admins only, customers see answered questions
+	IsAdmin   bool   `query:"-"`
+	*entities.PaginationReq
+}
+
+// Statuses are the statuses the filter accepts
+var Statuses = map[string]string{
+	"unanswered": `"q"."answer" IS NULL AND "q"."is_hidden" = FALSE`,
+	"answered":   `"q"."answer" IS NOT NULL AND "q"."is_hidden" = FALSE`,
+	"hidden":     `"q"."is_hidden" = TRUE`,
+}
+
+type Question struct {
+	Id           string  `json:"id"`
+	ProductId    string  `json:"product_id"`
+	UserId       string  `json:"user_id"`
+	Username     string  `json:"username"`
+	Question     string  `json:"question"`
+	Answer       *string `json:"answer"`
+	AnsweredAt   *string `json:"answered_at"`
+	HelpfulCount int     `json:"helpful_count"`
+	IsHidden     bool    `json:"is_hidden"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type QuestionReq struct {
+	ProductId string `json:"-"`
+	UserId    string `json:"-"`
+	Question  string `json:"question"`
+}
+
+type AnswerReq struct {
+	Id       string  `json:"-"`
+	UserId   string  `json:"-"`
+	Answer   *string `json:"answer"`    // "" takes the answer back
+	IsHidden *bool   `json:"is_hidden"` // hides the question from customers
+}
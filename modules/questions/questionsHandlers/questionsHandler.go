@@ -0,0 +1,228 @@
+package questionsHandlers
+
+import (
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/config"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/middlewares"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions/questionsUsecases"
+	"github.com/gofiber/fiber/v3"
+)
+
+type questionsHandlersErrCode string
+
+const (
+	findQuestionErr   questionsHandlersErrCode = "questions-001"
+	insertQuestionErr questionsHandlersErrCode = "questions-002"
+	answerQuestionErr questionsHandlersErrCode = "questions-003"
+	voteQuestionErr   questionsHandlersErrCode = "questions-004"
+)
+
+type IQuestionsHandler interface {
+	FindQuestion(c fiber.Ctx) error
+	FindProductQuestion(c fiber.Ctx) error
+	InsertQuestion(c fiber.Ctx) error
+	AnswerQuestion(c fiber.Ctx) error
+	VoteQuestion(c fiber.Ctx) error
+	UnvoteQuestion(c fiber.Ctx) error
+}
+
+type questionsHandler struct {
+	cfg              config.IConfig
+	questionsUsecase questionsUsecases.IQuestionsUsecase
+}
+
+func QuestionsHandler(cfg config.IConfig, questionsUsecase questionsUsecases.IQuestionsUsecase) IQuestionsHandler {
+	return &questionsHandler{
+		cfg:              cfg,
+		questionsUsecase: questionsUsecase,
+	}
+}
+
+// @Summary Find Questions
+// @Description Find questions of every product, e.g. the unanswered ones to answer them
+// @Tags Questions
+// @Accept  json
+// @Produce  json
+// @Param status query string false "Status (unanswered | answered | hidden)"
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(10)
+// @Security BearerAuth
+// @Success 200 {object} entities.PaginateRes
+// @Router /questions [get]
+func (h *questionsHandler) FindQuestion(c fiber.Ctx) error {
+	return h.findQuestion(c, "")
+}
+
+// @Summary Find Product Questions
+// @Description Find answered questions of a product, admins see every question
+// @Tags Questions
+// @Accept  json
+// @Produce  json
+// @Param product_id path string true "Product ID"
+// @Param status query string false "Status, admins only (unanswered | answered | hidden)"
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(10)
+// @Security BearerAuth
+// @Success 200 {object} entities.PaginateRes
+// @Router /products/{product_id}/questions [get]
+func (h *questionsHandler) FindProductQuestion(c fiber.Ctx) error {
+	return h.findQuestion(c, strings.Trim(c.Params("product_id"), " "))
+}
+
+func (h *questionsHandler) findQuestion(c fiber.Ctx, productId string) error {
+	req := &questions.QuestionFilter{
+		PaginationReq: &entities.PaginationReq{},
+	}
+
+	if err := c.Bind().Query(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findQuestionErr),
+			err.Error(),
+		).Res()
+	}
+	req.ProductId = productId
+
+	req.IsAdmin = middlewares.IsAdmin(c)
+	if !req.IsAdmin {
+		req.Status = ""
+	}
+	if _, ok := questions.Statuses[req.Status]; req.Status != "" && !ok {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findQuestionErr),
+			"status is invalid",
+		).Res()
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		req.Limit = 10
+	}
+
+	result, err := h.questionsUsecase.FindQuestion(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findQuestionErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Ask Question
+// @Description Ask a public question about a product, admins are notified
+// @Tags Questions
+// @Accept  json
+// @Produce  json
+// @Param product_id path string true "Product ID"
+// @Param request body questions.QuestionReq true "Question Request"
+// @Security BearerAuth
+// @Success 201 {object} questions.Question
+// @Router /products/{product_id}/questions [post]
+func (h *questionsHandler) InsertQuestion(c fiber.Ctx) error {
+	req := new(questions.QuestionReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertQuestionErr),
+			err.Error(),
+		).Res()
+	}
+	req.ProductId = strings.Trim(c.Params("product_id"), " ")
+	req.UserId = strings.Trim(c.Locals("userId").(string), " ")
+
+	result, err := h.questionsUsecase.InsertQuestion(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertQuestionErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, result).Res()
+}
+
+// @Summary Answer Question
+// @Description Answer or hide a question, the asker is notified of the first answer
+// @Tags Questions
+// @Accept  json
+// @Produce  json
+// @Param question_id path string true "Question ID"
+// @Param request body questions.AnswerReq true "Answer Request"
+// @Security BearerAuth
+// @Success 200 {object} questions.Question
+// @Router /questions/{question_id} [patch]
+func (h *questionsHandler) AnswerQuestion(c fiber.Ctx) error {
+	req := new(questions.AnswerReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(answerQuestionErr),
+			err.Error(),
+		).Res()
+	}
+	req.Id = strings.Trim(c.Params("question_id"), " ")
+	req.UserId = strings.Trim(c.Locals("userId").(string), " ")
+
+	result, err := h.questionsUsecase.AnswerQuestion(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(answerQuestionErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Vote Question Helpful
+// @Description Mark an answered question as helpful, once per user
+// @Tags Questions
+// @Accept  json
+// @Produce  json
+// @Param question_id path string true "Question ID"
+// @Security BearerAuth
+// @Success 200 {object} questions.Question
+// @Router /questions/{question_id}/helpful [post]
+func (h *questionsHandler) VoteQuestion(c fiber.Ctx) error {
+	return h.voteQuestion(c, true)
+}
+
+// @Summary Unvote Question Helpful
+// @Description Take back the helpful vote of a question
+// @Tags Questions
+// @Accept  json
+// @Produce  json
+// @Param question_id path string true "Question ID"
+// @Security BearerAuth
+// @Success 200 {object} questions.Question
+// @Router /questions/{question_id}/helpful [delete]
+func (h *questionsHandler) UnvoteQuestion(c fiber.Ctx) error {
+	return h.voteQuestion(c, false)
+}
+
+func (h *questionsHandler) voteQuestion(c fiber.Ctx, helpful bool) error {
+	questionId := strings.Trim(c.Params("question_id"), " ")
+	userId := strings.Trim(c.Locals("userId").(string), " ")
+
+	result, err := h.questionsUsecase.VoteQuestion(questionId, userId, helpful)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(voteQuestionErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
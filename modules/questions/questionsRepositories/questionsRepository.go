@@ -0,0 +1,303 @@
+package questionsRepositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions"
+	"github.com/jmoiron/sqlx"
+)
+
+type IQuestionsRepository interface {
+	FindQuestion(req *questions.QuestionFilter) ([]*questions.Question, int, error)
+	FindOneQuestion(questionId string) (*questions.Question, error)
+	IsPublishedProduct(productId string) (bool, error)
+	InsertQuestion(req *questions.QuestionReq) (string, error)
+	AnswerQuestion(req *questions.AnswerReq) error
+	VoteQuestion(questionId, userId string) error
+	UnvoteQuestion(questionId, userId string) error
+}
+
+type questionsRepository struct {
+	db *sqlx.DB
+}
+
+func QuestionsRepository(db *sqlx.DB) IQuestionsRepository {
+	return &questionsRepository{
+		db: db,
+	}
+}
+
+// questionColumns are the columns of a question "q" with the username of "u"
+const questionColumns = `
+	"q"."id",
+	"q"."product_id",
+	"q"."user_id",
+	"u"."username",
+	"q"."question",
+	"q"."answer",
+	"q"."answered_at",
+	(
+		SELECT
+			COUNT(*)
+		FROM "questions_votes" "qv"
+		WHERE "qv"."question_id" = "q"."id"
+	) AS "helpful_count",
+	"q"."is_hidden",
+	"q"."created_at"`
+
+// FindQuestion lists the questions, customers see answered questions only and
+// the most helpful come first. Unanswered questions are oldest first.
+func (r *questionsRepository) FindQuestion(req *questions.QuestionFilter) ([]*questions.Question, int, error) {
+	status := req.Status
+	if !req.IsAdmin {
+		status = "answered"
+	}
+
+	queryWhere := `
+	FROM "questions" "q"
+		INNER JOIN "users" "u" ON "u"."id" = "q"."user_id"
+	WHERE ($1 = '' OR "q"."product_id" = $1)`
+	if status != "" {
+		queryWhere += `
+	AND ` + questions.Statuses[status]
+	}
+
+	orderBy := `"helpful_count" DESC, "q"."answered_at" DESC NULLS LAST, "q"."id"`
+	if status == "unanswered" {
+		orderBy = `"q"."created_at" ASC, "q"."id"`
+	}
+
+	var count int
+	if err := r.db.Get(&count, `SELECT COUNT(*)`+queryWhere+`;`, req.ProductId); err != nil {
+		return nil, 0, fmt.Errorf("failed to count questions: %w", err)
+	}
+
+	query := `
+	SELECT
+		COALESCE(array_to_json(array_agg("t")), '[]'::json)
+	FROM (
+		SELECT` + questionColumns + queryWhere + `
+		ORDER BY ` + orderBy + `
+		OFFSET $2 LIMIT $3
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, req.ProductId, (req.Page-1)*req.Limit, req.Limit); err != nil {
+		return nil, 0, fmt.Errorf("failed to get questions: %w", err)
+	}
+
+	questionsData := make([]*questions.Question, 0)
+	if err := json.Unmarshal(raw, &questionsData); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal questions: %w", err)
+	}
+	return questionsData, count, nil
+}
+
+func (r *questionsRepository) FindOneQuestion(questionId string) (*questions.Question, error) {
+	query := `
+	SELECT
+		to_json("t")
+	FROM (
+		SELECT` + questionColumns + `
+		FROM "questions" "q"
+			INNER JOIN "users" "u" ON "u"."id" = "q"."user_id"
+		WHERE "q"."id"::TEXT = $1
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, questionId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("question not found")
+		}
+		return nil, fmt.Errorf("failed to get question: %w", err)
+	}
+
+	question := new(questions.Question)
+	if err := json.Unmarshal(raw, question); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal question: %w", err)
+	}
+	return question, nil
+}
+
+// IsPublishedProduct tells whether the product exists and customers can see it
+func (r *questionsRepository) IsPublishedProduct(productId string) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM "products" "p"
+		WHERE "p"."id" = $1
+		AND ` + products.PublishedQuery + `
+	);`
+
+	var ok bool
+	if err := r.db.Get(&ok, query, productId); err != nil {
+		return false, fmt.Errorf("failed to find product: %w", err)
+	}
+	return ok, nil
+}
+
+// InsertQuestion adds the question and notifies every admin in the same statement
+func (r *questionsRepository) InsertQuestion(req *questions.QuestionReq) (string, error) {
+	query := `
+	WITH "inserted" AS (
+		INSERT INTO "questions" (
+			"product_id",
+			"user_id",
+			"question"
+		)
+		VALUES ($1, $2, $3)
+		RETURNING "id", "product_id"
+	), "notified" AS (
+		INSERT INTO "notifications" (
+			"user_id",
+			"title",
+			"message"
+		)
+		SELECT
+			"u"."id",
+			'New question',
+			CONCAT('A question on ', "p"."title", ' is waiting for an answer')
+		FROM "inserted" "i"
+			INNER JOIN "products" "p" ON "p"."id" = "i"."product_id"
+			CROSS JOIN "users" "u"
+		WHERE "u"."role_id" = 2
+	)
+	SELECT
+		"id"::TEXT
+	FROM "inserted";`
+
+	var questionId string
+	if err := r.db.QueryRowxContext(
+		context.Background(),
+		query,
+		req.ProductId,
+		req.UserId,
+		req.Question,
+	).Scan(&questionId); err != nil {
+		return "", fmt.Errorf("failed to insert question: %w", err)
+	}
+	return questionId, nil
+}
+
+// AnswerQuestion sets the answer and the visibility, the asker is notified
+// the first time the question is answered.
+func (r *questionsRepository) AnswerQuestion(req *questions.AnswerReq) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var answer *string
+	if err := tx.GetContext(ctx, &answer, `SELECT "answer" FROM "questions" WHERE "id"::TEXT = $1 FOR UPDATE;`, req.Id); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("question not found")
+		}
+		return fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	query := `
+		UPDATE "questions" SET
+	`
+
+	queryWhereStack := make([]string, 0)
+	values := make([]any, 0)
+	lastIndex := 1
+
+	if req.Answer != nil {
+		values = append(values, *req.Answer, req.UserId)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"answer" = NULLIF($%d, ''),
+			"answered_by" = CASE WHEN $%d = '' THEN NULL ELSE $%d END,
+			"answered_at" = CASE WHEN $%d = '' THEN NULL ELSE now() END?`,
+			lastIndex, lastIndex, lastIndex+1, lastIndex,
+		))
+		lastIndex += 2
+	}
+
+	if req.IsHidden != nil {
+		values = append(values, *req.IsHidden)
+		queryWhereStack = append(queryWhereStack, fmt.Sprintf(`"is_hidden" = $%d?`, lastIndex))
+		lastIndex++
+	}
+
+	values = append(values, req.Id)
+
+	queryClose := fmt.Sprintf(` WHERE "id"::TEXT = $%d`, lastIndex)
+
+	for i := range queryWhereStack {
+		if i != len(queryWhereStack)-1 {
+			query += strings.Replace(queryWhereStack[i], "?", ",", 1)
+		} else {
+			query += strings.Replace(queryWhereStack[i], "?", "", 1)
+		}
+	}
+	query += queryClose
+
+	if _, err := tx.ExecContext(ctx, query, values...); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	if answer == nil && req.Answer != nil && *req.Answer != "" {
+		queryNotify := `
+		INSERT INTO "notifications" (
+			"user_id",
+			"title",
+			"message"
+		)
+		SELECT
+			"q"."user_id",
+			'Question answered',
+			CONCAT('Your question on ', "p"."title", ' has been answered')
+		FROM "questions" "q"
+			INNER JOIN "products" "p" ON "p"."id" = "q"."product_id"
+		WHERE "q"."id"::TEXT = $1;`
+
+		if _, err := tx.ExecContext(ctx, queryNotify, req.Id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to notify user: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// VoteQuestion marks the question helpful for the user, voting again is a no-op
+func (r *questionsRepository) VoteQuestion(questionId, userId string) error {
+	query := `
+	INSERT INTO "questions_votes" (
+		"question_id",
+		"user_id"
+	)
+	VALUES (($1)::uuid, $2)
+	ON CONFLICT ("question_id", "user_id") DO NOTHING;`
+
+	if _, err := r.db.ExecContext(context.Background(), query, questionId, userId); err != nil {
+		return fmt.Errorf("failed to vote question: %w", err)
+	}
+	return nil
+}
+
+func (r *questionsRepository) UnvoteQuestion(questionId, userId string) error {
+	query := `
+	DELETE FROM "questions_votes"
+	WHERE "question_id"::TEXT = $1
+	AND "user_id" = $2;`
+
+	if _, err := r.db.ExecContext(context.Background(), query, questionId, userId); err != nil {
+		return fmt.Errorf("failed to unvote question: %w", err)
+	}
+	return nil
+}
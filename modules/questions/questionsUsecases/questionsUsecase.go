@@ -0,0 +1,101 @@
+package questionsUsecases
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions/questionsRepositories"
+)
+
+type IQuestionsUsecase interface {
+	FindQuestion(req *questions.QuestionFilter) (*entities.PaginateRes, error)
+	InsertQuestion(req *questions.QuestionReq) (*questions.Question, error)
+	AnswerQuestion(req *questions.AnswerReq) (*questions.Question, error)
+	VoteQuestion(questionId, userId string, helpful bool) (*questions.Question, error)
+}
+
+type questionsUsecase struct {
+	questionsRepository questionsRepositories.IQuestionsRepository
+}
+
+func QuestionsUsecase(questionsRepository questionsRepositories.IQuestionsRepository) IQuestionsUsecase {
+	return &questionsUsecase{
+		questionsRepository: questionsRepository,
+	}
+}
+
+func (u *questionsUsecase) FindQuestion(req *questions.QuestionFilter) (*entities.PaginateRes, error) {
+	questionsData, count, err := u.questionsRepository.FindQuestion(req)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PaginateRes{
+		Data:      questionsData,
+		Page:      req.Page,
+		Limit:     req.Limit,
+		TotalItem: count,
+		TotalPage: int(math.Ceil(float64(count) / float64(req.Limit))),
+	}, nil
+}
+
+func (u *questionsUsecase) InsertQuestion(req *questions.QuestionReq) (*questions.Question, error) {
+	req.Question = strings.TrimSpace(req.Question)
+	if req.Question == "" {
+		return nil, fmt.Errorf("question is required")
+	}
+
+	// Drafts and archived products can't be asked about
+	ok, err := u.questionsRepository.IsPublishedProduct(req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("product not found")
+	}
+
+	questionId, err := u.questionsRepository.InsertQuestion(req)
+	if err != nil {
+		return nil, err
+	}
+	return u.questionsRepository.FindOneQuestion(questionId)
+}
+
+func (u *questionsUsecase) AnswerQuestion(req *questions.AnswerReq) (*questions.Question, error) {
+	if req.Answer == nil && req.IsHidden == nil {
+		return nil, fmt.Errorf("answer or is_hidden is required")
+	}
+	if req.Answer != nil {
+		answer := strings.TrimSpace(*req.Answer)
+		req.Answer = &answer
+	}
+
+	if err := u.questionsRepository.AnswerQuestion(req); err != nil {
+		return nil, err
+	}
+	return u.questionsRepository.FindOneQuestion(req.Id)
+}
+
+// VoteQuestion adds or takes back the helpful vote of the user, only the
+// answered questions customers can see take votes.
+func (u *questionsUsecase) VoteQuestion(questionId, userId string, helpful bool) (*questions.Question, error) {
+	question, err := u.questionsRepository.FindOneQuestion(questionId)
+	if err != nil {
+		return nil, err
+	}
+	if question.IsHidden || question.Answer == nil {
+		return nil, fmt.Errorf("question not found")
+	}
+
+	if helpful {
+		err = u.questionsRepository.VoteQuestion(questionId, userId)
+	} else {
+		err = u.questionsRepository.UnvoteQuestion(questionId, userId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.questionsRepository.FindOneQuestion(questionId)
+}
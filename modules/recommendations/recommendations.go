@@ -0,0 +1,25 @@
+package recommendations
+
+import "github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+
+const (
+	KindRelated        = "related"
+	KindBoughtTogether = "bought_together"
+)
+
+type RecommendationFilter struct {
+	ProductId string `query:"-"`
+	Kind      string `query:"-"`
+	Limit     int    `query:"limit"`
+}
+
+type Recommendation struct {
+	Id          string          `json:"id"`
+	Title       string          `json:"title"`
+	Price       float64         `json:"price"`
+	InStock     bool            `json:"in_stock"`
+	Rating      float64         `json:"rating"`
+	ReviewCount int             `json:"review_count"`
+	Image       *entities.Image `json:"image"` // the primary image
+	Score       float64         `json:"score"`
+}
@@ -0,0 +1,89 @@
+package recommendationsHandlers
+
+import (
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/config"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations/recommendationsUsecases"
+	"github.com/gofiber/fiber/v3"
+)
+
+type recommendationsHandlersErrCode string
+
+const (
+	findRelatedErr        recommendationsHandlersErrCode = "recommendations-001"
+	findBoughtTogetherErr recommendationsHandlersErrCode = "recommendations-002"
+)
+
+type IRecommendationsHandler interface {
+	FindRelated(c fiber.Ctx) error
+	FindBoughtTogether(c fiber.Ctx) error
+}
+
+type recommendationsHandler struct {
+	cfg                    config.IConfig
+	recommendationsUsecase recommendationsUsecases.IRecommendationsUsecase
+}
+
+func RecommendationsHandler(cfg config.IConfig, recommendationsUsecase recommendationsUsecases.IRecommendationsUsecase) IRecommendationsHandler {
+	return &recommendationsHandler{
+		cfg:                    cfg,
+		recommendationsUsecase: recommendationsUsecase,
+	}
+}
+
+// @Summary Find Related Products
+// @Description Products in the same categories or bought with the product, refreshed on a schedule
+// @Tags Recommendations
+// @Accept  json
+// @Produce  json
+// @Param product_id path string true "Product ID"
+// @Param limit query int false "Limit" default(10)
+// @Success 200 {array} recommendations.Recommendation
+// @Router /products/{product_id}/related [get]
+func (h *recommendationsHandler) FindRelated(c fiber.Ctx) error {
+	return h.findRecommendation(c, recommendations.KindRelated, findRelatedErr)
+}
+
+// @Summary Find Frequently Bought Together
+// @Description Products that were in the same completed orders as the product, refreshed on a schedule
+// @Tags Recommendations
+// @Accept  json
+// @Produce  json
+// @Param product_id path string true "Product ID"
+// @Param limit query int false "Limit" default(10)
+// @Success 200 {array} recommendations.Recommendation
+// @Router /products/{product_id}/bought-together [get]
+func (h *recommendationsHandler) FindBoughtTogether(c fiber.Ctx) error {
+	return h.findRecommendation(c, recommendations.KindBoughtTogether, findBoughtTogetherErr)
+}
+
+func (h *recommendationsHandler) findRecommendation(c fiber.Ctx, kind string, errCode recommendationsHandlersErrCode) error {
+	req := new(recommendations.RecommendationFilter)
+	if err := c.Bind().Query(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(errCode),
+			err.Error(),
+		).Res()
+	}
+	req.ProductId = strings.Trim(c.Params("product_id"), " ")
+	req.Kind = kind
+
+	if req.Limit < 1 || req.Limit > 20 {
+		req.Limit = 10
+	}
+
+	result, err := h.recommendationsUsecase.FindRecommendation(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(errCode),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
@@ -0,0 +1,85 @@
+package recommendationsRepositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/products"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations"
+	"github.com/jmoiron/sqlx"
+)
+
+type IRecommendationsRepository interface {
+	FindRecommendation(req *recommendations.RecommendationFilter) ([]*recommendations.Recommendation, error)
+	RefreshRecommendation() error
+}
+
+type recommendationsRepository struct {
+	db *sqlx.DB
+}
+
+func RecommendationsRepository(db *sqlx.DB) IRecommendationsRepository {
+	return &recommendationsRepository{
+		db: db,
+	}
+}
+
+// FindRecommendation reads the cached recommendations of the product, the
+// products customers can't see are left out.
+func (r *recommendationsRepository) FindRecommendation(req *recommendations.RecommendationFilter) ([]*recommendations.Recommendation, error) {
+	query := `
+	SELECT
+		COALESCE(array_to_json(array_agg("t")), '[]'::json)
+	FROM (
+		SELECT
+			"p"."id",
+			"p"."title",
+			"p"."price",
+			COALESCE("p"."stock" > 0, TRUE) AS "in_stock",
+			"p"."rating",
+			"p"."review_count",
+			(
+				SELECT
+					to_jsonb("it")
+				FROM (
+					SELECT
+						"i"."id",
+						"i"."filename",
+						"i"."url",
+						"i"."alt"
+					FROM "images" "i"
+					WHERE "i"."product_id" = "p"."id"
+					ORDER BY "i"."position", "i"."created_at"
+					LIMIT 1
+				) AS "it"
+			) AS "image",
+			"r"."score"
+		FROM "products_recommendations" "r"
+			INNER JOIN "products" "p" ON "p"."id" = "r"."recommended_id"
+		WHERE "r"."product_id" = $1
+		AND "r"."kind" = $2
+		AND ` + products.PublishedQuery + `
+		ORDER BY "r"."rank"
+		LIMIT $3
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, req.ProductId, req.Kind, req.Limit); err != nil {
+		return nil, fmt.Errorf("failed to get recommendations: %w", err)
+	}
+
+	recommendationsData := make([]*recommendations.Recommendation, 0)
+	if err := json.Unmarshal(raw, &recommendationsData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recommendations: %w", err)
+	}
+	return recommendationsData, nil
+}
+
+// RefreshRecommendation recomputes the cache, readers keep the old one until it is done
+func (r *recommendationsRepository) RefreshRecommendation() error {
+	if _, err := r.db.ExecContext(context.Background(), `REFRESH MATERIALIZED VIEW CONCURRENTLY "products_recommendations";`); err != nil {
+		return fmt.Errorf("failed to refresh recommendations: %w", err)
+	}
+	return nil
+}
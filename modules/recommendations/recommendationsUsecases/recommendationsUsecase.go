@@ -0,0 +1,33 @@
+package recommendationsUsecases
+
+import (
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations/recommendationsRepositories"
+)
+
+type IRecommendationsUsecase interface {
+	FindRecommendation(req *recommendations.RecommendationFilter) ([]*recommendations.Recommendation, error)
+	RefreshRecommendation() error
+}
+
+type recommendationsUsecase struct {
+	recommendationsRepository recommendationsRepositories.IRecommendationsRepository
+}
+
+func RecommendationsUsecase(recommendationsRepository recommendationsRepositories.IRecommendationsRepository) IRecommendationsUsecase {
+	return &recommendationsUsecase{
+		recommendationsRepository: recommendationsRepository,
+	}
+}
+
+func (u *recommendationsUsecase) FindRecommendation(req *recommendations.RecommendationFilter) ([]*recommendations.Recommendation, error) {
+	recommendationsData, err := u.recommendationsRepository.FindRecommendation(req)
+	if err != nil {
+		return nil, err
+	}
+	return recommendationsData, nil
+}
+
+func (u *recommendationsUsecase) RefreshRecommendation() error {
+	return u.recommendationsRepository.RefreshRecommendation()
+}
@@ -0,0 +1,57 @@
+package reports
+
+type ReportFilter struct {
+	StartDate string `query:"start_date"`
+	EndDate   string `query:"end_date"`
+	GroupBy   string `query:"group_by"` // day, week, month
+	OrderBy   string `query:"order_by"` // qty, revenue
+	Limit     int    `query:"limit"`
+}
+
+type Revenue struct {
+	Period  string  `db:"period" json:"period"`
+	Orders  int     `db:"orders" json:"orders"`
+	Revenue float64 `db:"revenue" json:"revenue"`
+}
+
+type OrderStatus struct {
+	Status string `db:"status" json:"status"`
+	Orders int    `db:"orders" json:"orders"`
+}
+
+type TopProduct struct {
+	Id      string  `db:"id" json:"id"`
+	Title   string  `db:"title" json:"title"`
+	Qty     int     `db:"qty" json:"qty"`
+	Revenue float64 `db:"revenue" json:"revenue"`
+}
+
+type TopCategory struct {
+	Id      int     `db:"id" json:"id"`
+	Title   string  `db:"title" json:"title"`
+	Qty     int     `db:"qty" json:"qty"`
+	Revenue float64 `db:"revenue" json:"revenue"`
+}
+
+type AverageOrderValue struct {
+	Orders            int     `db:"orders" json:"orders"`
+	Revenue           float64 `db:"revenue" json:"revenue"`
+	AverageOrderValue float64 `db:"average_order_value" json:"average_order_value"`
+}
+
+type Customer struct {
+	New       int `db:"new" json:"new"`
+	Returning int `db:"returning" json:"returning"`
+}
+
+type PriceFilter struct {
+	Date      string `query:"date"`
+	ProductId string `query:"product_id"`
+}
+
+type Price struct {
+	Id             string   `db:"id" json:"id"`
+	Title          string   `db:"title" json:"title"`
+	Price          float64  `db:"price" json:"price"`
+	CompareAtPrice *float64 `db:"compare_at_price" json:"compare_at_price"`
+}
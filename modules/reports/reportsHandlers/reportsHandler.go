@@ -0,0 +1,347 @@
+package reportsHandlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IzePhanthakarn/go-basic-shop/config"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reports"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reports/reportsUsecases"
+	"github.com/gofiber/fiber/v3"
+)
+
+type reportsHandlersErrCode string
+
+const (
+	findRevenueErr           reportsHandlersErrCode = "reports-001"
+	findOrderStatusErr       reportsHandlersErrCode = "reports-002"
+	findTopProductErr        reportsHandlersErrCode = "reports-003"
+	findTopCategoryErr       reportsHandlersErrCode = "reports-004"
+	findAverageOrderValueErr reportsHandlersErrCode = "reports-005"
+	findCustomerErr          reportsHandlersErrCode = "reports-006"
+	findPriceErr             reportsHandlersErrCode = "reports-007"
+)
+
+type IReportsHandler interface {
+	FindRevenue(c fiber.Ctx) error
+	FindOrderStatus(c fiber.Ctx) error
+	FindTopProduct(c fiber.Ctx) error
+	FindTopCategory(c fiber.Ctx) error
+	FindAverageOrderValue(c fiber.Ctx) error
+	FindCustomer(c fiber.Ctx) error
+	FindPrice(c fiber.Ctx) error
+}
+
+type reportsHandler struct {
+	cfg            config.IConfig
+	reportsUsecase reportsUsecases.IReportsUsecase
+}
+
+func ReportsHandler(cfg config.IConfig, reportsUsecase reportsUsecases.IReportsUsecase) IReportsHandler {
+	return &reportsHandler{
+		cfg:            cfg,
+		reportsUsecase: reportsUsecase,
+	}
+}
+
+// reportFilter binds the query and fills the defaults, the date range is the
+// last 30 days in the app timezone when it is not given.
+func (h *reportsHandler) reportFilter(c fiber.Ctx) (*reports.ReportFilter, error) {
+	req := new(reports.ReportFilter)
+	if err := c.Bind().Query(req); err != nil {
+		return nil, err
+	}
+
+	today := time.Now().In(h.cfg.App().Location())
+
+	end := today
+	if req.EndDate != "" {
+		t, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date")
+		}
+		end = t
+	}
+	req.EndDate = end.Format("2006-01-02")
+
+	start := end.AddDate(0, 0, -29)
+	if req.StartDate != "" {
+		t, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date")
+		}
+		start = t
+	}
+	req.StartDate = start.Format("2006-01-02")
+
+	if req.StartDate > req.EndDate {
+		return nil, fmt.Errorf("start date must be before end date")
+	}
+
+	groupByMap := map[string]string{
+		"day":   "day",
+		"week":  "week",
+		"month": "month",
+	}
+	req.GroupBy = groupByMap[strings.ToLower(req.GroupBy)]
+	if req.GroupBy == "" {
+		req.GroupBy = groupByMap["day"]
+	}
+
+	orderByMap := map[string]string{
+		"qty":     "qty",
+		"revenue": "revenue",
+	}
+	req.OrderBy = orderByMap[strings.ToLower(req.OrderBy)]
+	if req.OrderBy == "" {
+		req.OrderBy = orderByMap["revenue"]
+	}
+
+	if req.Limit < 1 || req.Limit > 100 {
+		req.Limit = 10
+	}
+
+	return req, nil
+}
+
+// @Summary Revenue Report
+// @Description Revenue and orders grouped by day, week or month
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Param group_by query string false "Group By (day | week | month)" default(day)
+// @Security BearerAuth
+// @Success 200 {array} reports.Revenue
+// @Router /reports/revenue [get]
+func (h *reportsHandler) FindRevenue(c fiber.Ctx) error {
+	req, err := h.reportFilter(c)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findRevenueErr),
+			err.Error(),
+		).Res()
+	}
+
+	result, err := h.reportsUsecase.FindRevenue(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findRevenueErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Order Status Report
+// @Description Order counts by status
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Security BearerAuth
+// @Success 200 {array} reports.OrderStatus
+// @Router /reports/orders-status [get]
+func (h *reportsHandler) FindOrderStatus(c fiber.Ctx) error {
+	req, err := h.reportFilter(c)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findOrderStatusErr),
+			err.Error(),
+		).Res()
+	}
+
+	result, err := h.reportsUsecase.FindOrderStatus(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findOrderStatusErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Top Products Report
+// @Description Top products by quantity or revenue
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Param order_by query string false "Order By (qty | revenue)" default(revenue)
+// @Param limit query int false "Limit" default(10)
+// @Security BearerAuth
+// @Success 200 {array} reports.TopProduct
+// @Router /reports/top-products [get]
+func (h *reportsHandler) FindTopProduct(c fiber.Ctx) error {
+	req, err := h.reportFilter(c)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findTopProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	result, err := h.reportsUsecase.FindTopProduct(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findTopProductErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Top Categories Report
+// @Description Top categories by quantity or revenue, a category also counts the sales of its subcategories
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Param order_by query string false "Order By (qty | revenue)" default(revenue)
+// @Param limit query int false "Limit" default(10)
+// @Security BearerAuth
+// @Success 200 {array} reports.TopCategory
+// @Router /reports/top-categories [get]
+func (h *reportsHandler) FindTopCategory(c fiber.Ctx) error {
+	req, err := h.reportFilter(c)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findTopCategoryErr),
+			err.Error(),
+		).Res()
+	}
+
+	result, err := h.reportsUsecase.FindTopCategory(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findTopCategoryErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Average Order Value Report
+// @Description Average order value
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Security BearerAuth
+// @Success 200 {object} reports.AverageOrderValue
+// @Router /reports/average-order-value [get]
+func (h *reportsHandler) FindAverageOrderValue(c fiber.Ctx) error {
+	req, err := h.reportFilter(c)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findAverageOrderValueErr),
+			err.Error(),
+		).Res()
+	}
+
+	result, err := h.reportsUsecase.FindAverageOrderValue(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findAverageOrderValueErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Customers Report
+// @Description New vs returning customers
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Security BearerAuth
+// @Success 200 {object} reports.Customer
+// @Router /reports/customers [get]
+func (h *reportsHandler) FindCustomer(c fiber.Ctx) error {
+	req, err := h.reportFilter(c)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findCustomerErr),
+			err.Error(),
+		).Res()
+	}
+
+	result, err := h.reportsUsecase.FindCustomer(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findCustomerErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Prices Report
+// @Description Price of each product at the end of the date
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Param product_id query string false "Product ID"
+// @Security BearerAuth
+// @Success 200 {array} reports.Price
+// @Router /reports/prices [get]
+func (h *reportsHandler) FindPrice(c fiber.Ctx) error {
+	req := new(reports.PriceFilter)
+	if err := c.Bind().Query(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findPriceErr),
+			err.Error(),
+		).Res()
+	}
+
+	if req.Date == "" {
+		req.Date = time.Now().In(h.cfg.App().Location()).Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findPriceErr),
+			"invalid date",
+		).Res()
+	}
+
+	result, err := h.reportsUsecase.FindPrice(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findPriceErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
@@ -0,0 +1,265 @@
+package reportsRepositories
+
+import (
+	"fmt"
+
+	"github.com/IzePhanthakarn/go-basic-shop/config"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reports"
+	"github.com/jmoiron/sqlx"
+)
+
+// created_at is stored in the database timezone, shift it to the app timezone ($1)
+const localCreatedAt = `(("o"."created_at" AT TIME ZONE current_setting('TimeZone')) AT TIME ZONE $1)`
+
+// whereDate filters orders between start date ($2) and end date ($3), both inclusive
+const whereDate = `
+	AND ` + localCreatedAt + ` >= ($2)::DATE
+	AND ` + localCreatedAt + ` < ($3)::DATE + 1`
+
+// wherePaid keeps the orders whose slip has been approved, waiting orders
+// aren't revenue yet
+const wherePaid = `"o"."status" IN ('shipping', 'completed')`
+
+type IReportsRepository interface {
+	FindRevenue(req *reports.ReportFilter) ([]*reports.Revenue, error)
+	FindOrderStatus(req *reports.ReportFilter) ([]*reports.OrderStatus, error)
+	FindTopProduct(req *reports.ReportFilter) ([]*reports.TopProduct, error)
+	FindTopCategory(req *reports.ReportFilter) ([]*reports.TopCategory, error)
+	FindAverageOrderValue(req *reports.ReportFilter) (*reports.AverageOrderValue, error)
+	FindCustomer(req *reports.ReportFilter) (*reports.Customer, error)
+	FindPrice(req *reports.PriceFilter) ([]*reports.Price, error)
+}
+
+type reportsRepository struct {
+	db  *sqlx.DB
+	cfg config.IConfig
+}
+
+func ReportsRepository(db *sqlx.DB, cfg config.IConfig) IReportsRepository {
+	return &reportsRepository{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+func (r *reportsRepository) FindRevenue(req *reports.ReportFilter) ([]*reports.Revenue, error) {
+	query := `
+	SELECT
+		to_char(date_trunc($4, ` + localCreatedAt + `), 'YYYY-MM-DD') AS "period",
+		COUNT(DISTINCT "o"."id") AS "orders",
+		COALESCE(SUM(("po"."product"->>'price')::FLOAT * ("po"."qty")::FLOAT), 0) AS "revenue"
+	FROM "orders" "o"
+		LEFT JOIN "products_orders" "po" ON "po"."order_id" = "o"."id"
+	WHERE ` + wherePaid + whereDate + `
+	GROUP BY 1
+	ORDER BY 1 ASC;`
+
+	revenue := make([]*reports.Revenue, 0)
+	if err := r.db.Select(
+		&revenue,
+		query,
+		r.cfg.App().TimeZone(),
+		req.StartDate,
+		req.EndDate,
+		req.GroupBy,
+	); err != nil {
+		return nil, fmt.Errorf("failed to find revenue: %w", err)
+	}
+	return revenue, nil
+}
+
+func (r *reportsRepository) FindOrderStatus(req *reports.ReportFilter) ([]*reports.OrderStatus, error) {
+	query := `
+	SELECT
+		"o"."status",
+		COUNT(*) AS "orders"
+	FROM "orders" "o"
+	WHERE 1 = 1` + whereDate + `
+	GROUP BY "o"."status"
+	ORDER BY "o"."status" ASC;`
+
+	status := make([]*reports.OrderStatus, 0)
+	if err := r.db.Select(
+		&status,
+		query,
+		r.cfg.App().TimeZone(),
+		req.StartDate,
+		req.EndDate,
+	); err != nil {
+		return nil, fmt.Errorf("failed to find order status: %w", err)
+	}
+	return status, nil
+}
+
+func (r *reportsRepository) FindTopProduct(req *reports.ReportFilter) ([]*reports.TopProduct, error) {
+	query := fmt.Sprintf(`
+	SELECT
+		"po"."product"->>'id' AS "id",
+		MAX("po"."product"->>'title') AS "title",
+		SUM("po"."qty") AS "qty",
+		SUM(("po"."product"->>'price')::FLOAT * ("po"."qty")::FLOAT) AS "revenue"
+	FROM "products_orders" "po"
+		INNER JOIN "orders" "o" ON "o"."id" = "po"."order_id"
+	WHERE `+wherePaid+whereDate+`
+	GROUP BY "po"."product"->>'id'
+	ORDER BY "%s" DESC
+	LIMIT $4;`,
+		req.OrderBy,
+	)
+
+	products := make([]*reports.TopProduct, 0)
+	if err := r.db.Select(
+		&products,
+		query,
+		r.cfg.App().TimeZone(),
+		req.StartDate,
+		req.EndDate,
+		req.Limit,
+	); err != nil {
+		return nil, fmt.Errorf("failed to find top products: %w", err)
+	}
+	return products, nil
+}
+
+// FindTopCategory counts an order line in every category of its product and in
+// their ancestors, a line is counted once per category even when the product
+// is in many of its descendants. The categories are the ones the product has
+// now, not the snapshot of the order.
+func (r *reportsRepository) FindTopCategory(req *reports.ReportFilter) ([]*reports.TopCategory, error) {
+	query := fmt.Sprintf(`
+	SELECT
+		"c"."id",
+		"c"."title",
+		SUM("t"."qty") AS "qty",
+		SUM("t"."revenue") AS "revenue"
+	FROM (
+		SELECT DISTINCT
+			"po"."id",
+			"cc"."ancestor_id",
+			"po"."qty",
+			("po"."product"->>'price')::FLOAT * ("po"."qty")::FLOAT AS "revenue"
+		FROM "products_orders" "po"
+			INNER JOIN "orders" "o" ON "o"."id" = "po"."order_id"
+			INNER JOIN "products_categories" "pc" ON "pc"."product_id" = "po"."product"->>'id'
+			INNER JOIN "categories_closure" "cc" ON "cc"."descendant_id" = "pc"."category_id"
+		WHERE `+wherePaid+whereDate+`
+	) AS "t"
+		INNER JOIN "categories" "c" ON "c"."id" = "t"."ancestor_id"
+	GROUP BY "c"."id"
+	ORDER BY "%s" DESC
+	LIMIT $4;`,
+		req.OrderBy,
+	)
+
+	categories := make([]*reports.TopCategory, 0)
+	if err := r.db.Select(
+		&categories,
+		query,
+		r.cfg.App().TimeZone(),
+		req.StartDate,
+		req.EndDate,
+		req.Limit,
+	); err != nil {
+		return nil, fmt.Errorf("failed to find top categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (r *reportsRepository) FindAverageOrderValue(req *reports.ReportFilter) (*reports.AverageOrderValue, error) {
+	query := `
+	SELECT
+		COUNT(*) AS "orders",
+		COALESCE(SUM("t"."total"), 0) AS "revenue",
+		COALESCE(AVG("t"."total"), 0) AS "average_order_value"
+	FROM (
+		SELECT
+			SUM(("po"."product"->>'price')::FLOAT * ("po"."qty")::FLOAT) AS "total"
+		FROM "orders" "o"
+			INNER JOIN "products_orders" "po" ON "po"."order_id" = "o"."id"
+		WHERE ` + wherePaid + whereDate + `
+		GROUP BY "o"."id"
+	) AS "t";`
+
+	aov := new(reports.AverageOrderValue)
+	if err := r.db.Get(
+		aov,
+		query,
+		r.cfg.App().TimeZone(),
+		req.StartDate,
+		req.EndDate,
+	); err != nil {
+		return nil, fmt.Errorf("failed to find average order value: %w", err)
+	}
+	return aov, nil
+}
+
+// FindCustomer counts customers who ordered in the range, a customer is new
+// when their first order ever was placed inside the range.
+func (r *reportsRepository) FindCustomer(req *reports.ReportFilter) (*reports.Customer, error) {
+	query := `
+	SELECT
+		COUNT(*) FILTER (WHERE "f"."first_order_at" >= ($2)::DATE) AS "new",
+		COUNT(*) FILTER (WHERE "f"."first_order_at" < ($2)::DATE) AS "returning"
+	FROM (
+		SELECT DISTINCT
+			"o"."user_id"
+		FROM "orders" "o"
+		WHERE "o"."status" != 'canceled'` + whereDate + `
+	) AS "c"
+		INNER JOIN (
+			SELECT
+				"o"."user_id",
+				MIN(` + localCreatedAt + `) AS "first_order_at"
+			FROM "orders" "o"
+			WHERE "o"."status" != 'canceled'
+			GROUP BY "o"."user_id"
+		) AS "f" ON "f"."user_id" = "c"."user_id";`
+
+	customer := new(reports.Customer)
+	if err := r.db.Get(
+		customer,
+		query,
+		r.cfg.App().TimeZone(),
+		req.StartDate,
+		req.EndDate,
+	); err != nil {
+		return nil, fmt.Errorf("failed to find customers: %w", err)
+	}
+	return customer, nil
+}
+
+// FindPrice lists the price each product had at the end of the date ($2) in
+// the app timezone, products that had no price yet are left out.
+func (r *reportsRepository) FindPrice(req *reports.PriceFilter) ([]*reports.Price, error) {
+	query := `
+	SELECT
+		"p"."id",
+		"p"."title",
+		"pp"."price",
+		"pp"."compare_at_price"
+	FROM "products" "p"
+		INNER JOIN LATERAL (
+			SELECT
+				"pp"."price",
+				"pp"."compare_at_price"
+			FROM "products_prices" "pp"
+			WHERE "pp"."product_id" = "p"."id"
+			AND "pp"."effective_from" < ((($2)::DATE + 1)::TIMESTAMP AT TIME ZONE $1) AT TIME ZONE current_setting('TimeZone')
+			ORDER BY "pp"."effective_from" DESC
+			LIMIT 1
+		) AS "pp" ON TRUE
+	WHERE ($3 = '' OR "p"."id" = $3)
+	ORDER BY "p"."title" ASC, "p"."id" ASC;`
+
+	prices := make([]*reports.Price, 0)
+	if err := r.db.Select(
+		&prices,
+		query,
+		r.cfg.App().TimeZone(),
+		req.Date,
+		req.ProductId,
+	); err != nil {
+		return nil, fmt.Errorf("failed to find prices: %w", err)
+	}
+	return prices, nil
+}
@@ -0,0 +1,82 @@
+package reportsUsecases
+
+import (
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reports"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reports/reportsRepositories"
+)
+
+type IReportsUsecase interface {
+	FindRevenue(req *reports.ReportFilter) ([]*reports.Revenue, error)
+	FindOrderStatus(req *reports.ReportFilter) ([]*reports.OrderStatus, error)
+	FindTopProduct(req *reports.ReportFilter) ([]*reports.TopProduct, error)
+	FindTopCategory(req *reports.ReportFilter) ([]*reports.TopCategory, error)
+	FindAverageOrderValue(req *reports.ReportFilter) (*reports.AverageOrderValue, error)
+	FindCustomer(req *reports.ReportFilter) (*reports.Customer, error)
+	FindPrice(req *reports.PriceFilter) ([]*reports.Price, error)
+}
+
+type reportsUsecase struct {
+	reportsRepository reportsRepositories.IReportsRepository
+}
+
+func ReportsUsecase(reportsRepository reportsRepositories.IReportsRepository) IReportsUsecase {
+	return &reportsUsecase{
+		reportsRepository: reportsRepository,
+	}
+}
+
+func (u *reportsUsecase) FindRevenue(req *reports.ReportFilter) ([]*reports.Revenue, error) {
+	revenue, err := u.reportsRepository.FindRevenue(req)
+	if err != nil {
+		return nil, err
+	}
+	return revenue, nil
+}
+
+func (u *reportsUsecase) FindOrderStatus(req *reports.ReportFilter) ([]*reports.OrderStatus, error) {
+	status, err := u.reportsRepository.FindOrderStatus(req)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (u *reportsUsecase) FindTopProduct(req *reports.ReportFilter) ([]*reports.TopProduct, error) {
+	products, err := u.reportsRepository.FindTopProduct(req)
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (u *reportsUsecase) FindTopCategory(req *reports.ReportFilter) ([]*reports.TopCategory, error) {
+	categories, err := u.reportsRepository.FindTopCategory(req)
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (u *reportsUsecase) FindAverageOrderValue(req *reports.ReportFilter) (*reports.AverageOrderValue, error) {
+	aov, err := u.reportsRepository.FindAverageOrderValue(req)
+	if err != nil {
+		return nil, err
+	}
+	return aov, nil
+}
+
+func (u *reportsUsecase) FindCustomer(req *reports.ReportFilter) (*reports.Customer, error) {
+	customer, err := u.reportsRepository.FindCustomer(req)
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (u *reportsUsecase) FindPrice(req *reports.PriceFilter) ([]*reports.Price, error) {
+	prices, err := u.reportsRepository.FindPrice(req)
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
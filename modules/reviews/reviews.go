@@ -0,0 +1,47 @@
+package reviews
+
+import "github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+
+// ImageLimit is the most photos a review can have
+const ImageLimit = 5
+
+type ReviewFilter struct {
+	ProductId string `query:"-"`
+	Status    string `query:"status"` // admins only, customers see approved reviews
+	Rating    int    `query:"rating"`
+	IsAdmin   bool   `query:"-"`
+	*entities.PaginationReq
+}
+
+// Statuses are the statuses a review can have, new reviews wait for an admin
+var Statuses = map[string]bool{
+	"pending":  true,
+	"approved": true,
+	"hidden":   true,
+}
+
+type Review struct {
+	Id        string            `db:"id" json:"id"`
+	ProductId string            `db:"product_id" json:"product_id"`
+	UserId    string            `db:"user_id" json:"user_id"`
+	Username  string            `db:"username" json:"username"`
+	Rating    int               `db:"rating" json:"rating"`
+	Message   string            `db:"message" json:"message"`
+	Images    []*entities.Image `db:"images" json:"images"`
+	Status    string            `db:"status" json:"status"`
+	CreatedAt string            `db:"created_at" json:"created_at"`
+	UpdatedAt string            `db:"updated_at" json:"updated_at"`
+}
+
+type ReviewReq struct {
+	ProductId string            `json:"-"`
+	UserId    string            `json:"-"`
+	Rating    int               `json:"rating"` // 1-5
+	Message   string            `json:"message"`
+	Images    []*entities.Image `json:"images"`
+}
+
+type ModerateReq struct {
+	Id     string `json:"-"`
+	Status string `json:"status"` // pending | approved | hidden
+}
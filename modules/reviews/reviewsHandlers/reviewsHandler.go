@@ -0,0 +1,191 @@
+package reviewsHandlers
+
+import (
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/config"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/middlewares"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reviews"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reviews/reviewsUsecases"
+	"github.com/gofiber/fiber/v3"
+)
+
+type reviewsHandlersErrCode string
+
+const (
+	findReviewErr     reviewsHandlersErrCode = "reviews-001"
+	insertReviewErr   reviewsHandlersErrCode = "reviews-002"
+	moderateReviewErr reviewsHandlersErrCode = "reviews-003"
+)
+
+type IReviewsHandler interface {
+	FindReview(c fiber.Ctx) error
+	FindProductReview(c fiber.Ctx) error
+	InsertReview(c fiber.Ctx) error
+	ModerateReview(c fiber.Ctx) error
+}
+
+type reviewsHandler struct {
+	cfg            config.IConfig
+	reviewsUsecase reviewsUsecases.IReviewsUsecase
+}
+
+func ReviewsHandler(cfg config.IConfig, reviewsUsecase reviewsUsecases.IReviewsUsecase) IReviewsHandler {
+	return &reviewsHandler{
+		cfg:            cfg,
+		reviewsUsecase: reviewsUsecase,
+	}
+}
+
+// @Summary Find Reviews
+// @Description Find reviews of every product to moderate them
+// @Tags Reviews
+// @Accept  json
+// @Produce  json
+// @Param status query string false "Status (pending | approved | hidden)"
+// @Param rating query int false "Rating (1-5)"
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(10)
+// @Security BearerAuth
+// @Success 200 {object} entities.PaginateRes
+// @Router /reviews [get]
+func (h *reviewsHandler) FindReview(c fiber.Ctx) error {
+	return h.findReview(c, "")
+}
+
+// @Summary Find Product Reviews
+// @Description Find approved reviews of a product, admins see every review
+// @Tags Reviews
+// @Accept  json
+// @Produce  json
+// @Param product_id path string true "Product ID"
+// @Param status query string false "Status, admins only (pending | approved | hidden)"
+// @Param rating query int false "Rating (1-5)"
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(10)
+// @Security BearerAuth
+// @Success 200 {object} entities.PaginateRes
+// @Router /products/{product_id}/reviews [get]
+func (h *reviewsHandler) FindProductReview(c fiber.Ctx) error {
+	return h.findReview(c, strings.Trim(c.Params("product_id"), " "))
+}
+
+func (h *reviewsHandler) findReview(c fiber.Ctx, productId string) error {
+	req := &reviews.ReviewFilter{
+		PaginationReq: &entities.PaginationReq{},
+	}
+
+	if err := c.Bind().Query(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findReviewErr),
+			err.Error(),
+		).Res()
+	}
+	req.ProductId = productId
+
+	req.IsAdmin = middlewares.IsAdmin(c)
+	if !req.IsAdmin {
+		req.Status = ""
+	}
+	if req.Status != "" && !reviews.Statuses[req.Status] {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findReviewErr),
+			"status is invalid",
+		).Res()
+	}
+	if req.Rating < 0 || req.Rating > 5 {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(findReviewErr),
+			"rating must be between 1 and 5",
+		).Res()
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		req.Limit = 10
+	}
+
+	result, err := h.reviewsUsecase.FindReview(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusInternalServerError,
+			string(findReviewErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
+
+// @Summary Add Review
+// @Description Review a product from a completed order, it shows up once an admin approves it
+// @Tags Reviews
+// @Accept  json
+// @Produce  json
+// @Param product_id path string true "Product ID"
+// @Param request body reviews.ReviewReq true "Review Request"
+// @Security BearerAuth
+// @Success 201 {object} reviews.Review
+// @Router /products/{product_id}/reviews [post]
+func (h *reviewsHandler) InsertReview(c fiber.Ctx) error {
+	req := new(reviews.ReviewReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertReviewErr),
+			err.Error(),
+		).Res()
+	}
+	req.ProductId = strings.Trim(c.Params("product_id"), " ")
+	req.UserId = strings.Trim(c.Locals("userId").(string), " ")
+
+	result, err := h.reviewsUsecase.InsertReview(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(insertReviewErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusCreated, result).Res()
+}
+
+// @Summary Moderate Review
+// @Description Approve or hide a review, only approved reviews count in the product rating
+// @Tags Reviews
+// @Accept  json
+// @Produce  json
+// @Param review_id path string true "Review ID"
+// @Param request body reviews.ModerateReq true "Moderate Request"
+// @Security BearerAuth
+// @Success 200 {object} reviews.Review
+// @Router /reviews/{review_id} [patch]
+func (h *reviewsHandler) ModerateReview(c fiber.Ctx) error {
+	req := new(reviews.ModerateReq)
+	if err := c.Bind().JSON(req); err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(moderateReviewErr),
+			err.Error(),
+		).Res()
+	}
+	req.Id = strings.Trim(c.Params("review_id"), " ")
+
+	result, err := h.reviewsUsecase.ModerateReview(req)
+	if err != nil {
+		return entities.NewResponse(c).Error(
+			fiber.StatusBadRequest,
+			string(moderateReviewErr),
+			err.Error(),
+		).Res()
+	}
+
+	return entities.NewResponse(c).Success(fiber.StatusOK, result).Res()
+}
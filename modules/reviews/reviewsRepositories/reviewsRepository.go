@@ -0,0 +1,175 @@
+package reviewsRepositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reviews"
+	"github.com/jmoiron/sqlx"
+)
+
+type IReviewsRepository interface {
+	FindReview(req *reviews.ReviewFilter) ([]*reviews.Review, int, error)
+	FindOneReview(reviewId string) (*reviews.Review, error)
+	HasCompletedOrder(userId, productId string) (bool, error)
+	InsertReview(req *reviews.ReviewReq) (string, error)
+	ModerateReview(req *reviews.ModerateReq) error
+}
+
+type reviewsRepository struct {
+	db *sqlx.DB
+}
+
+func ReviewsRepository(db *sqlx.DB) IReviewsRepository {
+	return &reviewsRepository{
+		db: db,
+	}
+}
+
+// reviewColumns are the columns of a review "r" with the username of "u"
+const reviewColumns = `
+	"r"."id",
+	"r"."product_id",
+	"r"."user_id",
+	"u"."username",
+	"r"."rating",
+	"r"."message",
+	"r"."images",
+	"r"."status",
+	"r"."created_at",
+	"r"."updated_at"`
+
+// FindReview lists the reviews newest first, customers see approved reviews only.
+func (r *reviewsRepository) FindReview(req *reviews.ReviewFilter) ([]*reviews.Review, int, error) {
+	status := req.Status
+	if !req.IsAdmin {
+		status = "approved"
+	}
+
+	queryWhere := `
+	FROM "reviews" "r"
+		INNER JOIN "users" "u" ON "u"."id" = "r"."user_id"
+	WHERE ($1 = '' OR "r"."product_id" = $1)
+	AND ($2 = '' OR "r"."status"::TEXT = $2)
+	AND ($3 = 0 OR "r"."rating" = $3)`
+
+	var count int
+	if err := r.db.Get(&count, `SELECT COUNT(*)`+queryWhere+`;`, req.ProductId, status, req.Rating); err != nil {
+		return nil, 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	query := `
+	SELECT
+		COALESCE(array_to_json(array_agg("t")), '[]'::json)
+	FROM (
+		SELECT` + reviewColumns + queryWhere + `
+		ORDER BY "r"."created_at" DESC, "r"."id" DESC
+		OFFSET $4 LIMIT $5
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, req.ProductId, status, req.Rating, (req.Page-1)*req.Limit, req.Limit); err != nil {
+		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
+	}
+
+	reviewsData := make([]*reviews.Review, 0)
+	if err := json.Unmarshal(raw, &reviewsData); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal reviews: %w", err)
+	}
+	return reviewsData, count, nil
+}
+
+func (r *reviewsRepository) FindOneReview(reviewId string) (*reviews.Review, error) {
+	query := `
+	SELECT
+		to_json("t")
+	FROM (
+		SELECT` + reviewColumns + `
+		FROM "reviews" "r"
+			INNER JOIN "users" "u" ON "u"."id" = "r"."user_id"
+		WHERE "r"."id"::TEXT = $1
+	) AS "t";`
+
+	raw := make([]byte, 0)
+	if err := r.db.Get(&raw, query, reviewId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("review not found")
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	review := new(reviews.Review)
+	if err := json.Unmarshal(raw, review); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal review: %w", err)
+	}
+	return review, nil
+}
+
+// HasCompletedOrder tells whether the user has a completed order with the product
+func (r *reviewsRepository) HasCompletedOrder(userId, productId string) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM "orders" "o"
+			INNER JOIN "products_orders" "po" ON "po"."order_id" = "o"."id"
+		WHERE "o"."user_id" = $1
+		AND "o"."status" = 'completed'
+		AND "po"."product"->>'id' = $2
+	);`
+
+	var ok bool
+	if err := r.db.Get(&ok, query, userId, productId); err != nil {
+		return false, fmt.Errorf("failed to find orders: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *reviewsRepository) InsertReview(req *reviews.ReviewReq) (string, error) {
+	query := `
+	INSERT INTO "reviews" (
+		"product_id",
+		"user_id",
+		"rating",
+		"message",
+		"images"
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT ("product_id", "user_id") DO NOTHING
+	RETURNING "id"::TEXT;`
+
+	var reviewId string
+	if err := r.db.QueryRowxContext(
+		context.Background(),
+		query,
+		req.ProductId,
+		req.UserId,
+		req.Rating,
+		req.Message,
+		req.Images,
+	).Scan(&reviewId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("product has already been reviewed")
+		}
+		return "", fmt.Errorf("failed to insert review: %w", err)
+	}
+	return reviewId, nil
+}
+
+func (r *reviewsRepository) ModerateReview(req *reviews.ModerateReq) error {
+	query := `
+	UPDATE "reviews" SET
+		"status" = $1
+	WHERE "id"::TEXT = $2;`
+
+	result, err := r.db.ExecContext(context.Background(), query, req.Status, req.Id)
+	if err != nil {
+		return fmt.Errorf("failed to moderate review: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("review not found")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package reviewsUsecases
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/entities"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reviews"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reviews/reviewsRepositories"
+)
+
+type IReviewsUsecase interface {
+	FindReview(req *reviews.ReviewFilter) (*entities.PaginateRes, error)
+	InsertReview(req *reviews.ReviewReq) (*reviews.Review, error)
+	ModerateReview(req *reviews.ModerateReq) (*reviews.Review, error)
+}
+
+type reviewsUsecase struct {
+	reviewsRepository reviewsRepositories.IReviewsRepository
+}
+
+func ReviewsUsecase(reviewsRepository reviewsRepositories.IReviewsRepository) IReviewsUsecase {
+	return &reviewsUsecase{
+		reviewsRepository: reviewsRepository,
+	}
+}
+
+func (u *reviewsUsecase) FindReview(req *reviews.ReviewFilter) (*entities.PaginateRes, error) {
+	reviewsData, count, err := u.reviewsRepository.FindReview(req)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PaginateRes{
+		Data:      reviewsData,
+		Page:      req.Page,
+		Limit:     req.Limit,
+		TotalItem: count,
+		TotalPage: int(math.Ceil(float64(count) / float64(req.Limit))),
+	}, nil
+}
+
+// InsertReview lets a customer review a product they have received, the
+// review shows up once an admin approves it.
+func (u *reviewsUsecase) InsertReview(req *reviews.ReviewReq) (*reviews.Review, error) {
+	if req.Rating < 1 || req.Rating > 5 {
+		return nil, fmt.Errorf("rating must be between 1 and 5")
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Images == nil {
+		req.Images = make([]*entities.Image, 0)
+	}
+	if len(req.Images) > reviews.ImageLimit {
+		return nil, fmt.Errorf("a review can have at most %d images", reviews.ImageLimit)
+	}
+
+	ok, err := u.reviewsRepository.HasCompletedOrder(req.UserId, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("only customers with a completed order of the product can review it")
+	}
+
+	reviewId, err := u.reviewsRepository.InsertReview(req)
+	if err != nil {
+		return nil, err
+	}
+	return u.reviewsRepository.FindOneReview(reviewId)
+}
+
+func (u *reviewsUsecase) ModerateReview(req *reviews.ModerateReq) (*reviews.Review, error) {
+	if !reviews.Statuses[req.Status] {
+		return nil, fmt.Errorf("status is invalid")
+	}
+
+	if err := u.reviewsRepository.ModerateReview(req); err != nil {
+		return nil, err
+	}
+	return u.reviewsRepository.FindOneReview(req.Id)
+}
@@ -5,15 +5,28 @@ import (
 	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo/appinfoHandlers"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo/appinfoRepositories"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo/appinfoUsecases"
-	"github.com/IzePhanthakarn/go-basic-shop/modules/files/filesUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/flashsales/flashsalesHandlers"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/flashsales/flashsalesRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/flashsales/flashsalesUsecases"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/middlewares/middlewaresHandlers"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/middlewares/middlewaresRepositories"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/middlewares/middlewaresUsecases"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/monitor/monitorHandlers"
-	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersHandlers"
-	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersRepositories"
-	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersUsecases"
-	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/notifications/notificationsHandlers"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/notifications/notificationsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/notifications/notificationsUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions/questionsHandlers"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions/questionsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions/questionsUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations/recommendationsHandlers"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations/recommendationsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations/recommendationsUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reports/reportsHandlers"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reports/reportsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reports/reportsUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reviews/reviewsHandlers"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reviews/reviewsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/reviews/reviewsUsecases"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/users/usersHandlers"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/users/usersRepositories"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/users/usersUsecases"
@@ -26,7 +39,13 @@ type IModuleFactory interface {
 	AppinfoModule()
 	FileModule() IFileModule
 	ProductsModule() IProductsModule
-	OrderModule()
+	OrderModule() IOrderModule
+	FlashSaleModule()
+	ReviewModule()
+	QuestionModule()
+	RecommendationModule()
+	NotificationModule()
+	ReportModule()
 	SwaggerModule()
 }
 
@@ -85,26 +104,97 @@ func (m *moduleFactory) AppinfoModule() {
 	router := m.router.Group("/appinfo")
 
 	router.Post("/categories", handler.AddCategory, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Patch("/categories/:category_id", handler.UpdateCategory, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
 	router.Delete("/categories/:category_id", handler.RemoveCategory, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Post("/categories/:category_id/attributes", handler.AddAttribute, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Patch("/categories/:category_id/attributes/:attribute_id", handler.UpdateAttribute, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Delete("/categories/:category_id/attributes/:attribute_id", handler.RemoveAttribute, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
 
 	router.Get("/categories", handler.FindCategory)
+	router.Get("/categories/tree", handler.FindCategoryTree)
+	router.Get("/categories/by-slug/:slug", handler.FindCategoryBySlug)
+	router.Get("/categories/:category_id/attributes", handler.FindAttribute)
 	router.Get("/apikey", handler.GenerateApiKey, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
 }
 
-func (m *moduleFactory) OrderModule() {
-	filesUsecase := filesUsecases.FileUsecase(m.server.cfg)
-	productsRepository := productsRepositories.ProductsRepository(m.server.db, m.server.cfg, filesUsecase)
+func (m *moduleFactory) FlashSaleModule() {
+	repository := flashsalesRepositories.FlashsalesRepository(m.server.db)
+	usecase := flashsalesUsecases.FlashsalesUsecase(repository)
+	handler := flashsalesHandlers.FlashsalesHandler(m.server.cfg, usecase)
 
-	ordersRepository := ordersRepositories.OrdersRepository(m.server.db)
-	ordersUsecase := ordersUsecases.OrderUsecase(ordersRepository, productsRepository)
-	ordersHandler := ordersHandlers.OrdersHandlers(m.server.cfg, ordersUsecase)
+	router := m.router.Group("/flash-sales")
 
-	router := m.router.Group("/orders")
+	router.Get("/", handler.FindFlashSale)
+	router.Get("/:flash_sale_id", handler.FindOneFlashSale)
 
-	router.Post("/", ordersHandler.InsertOrder, m.middlewares.JwtAuth())
+	router.Post("/", handler.InsertFlashSale, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Patch("/:flash_sale_id", handler.UpdateFlashSale, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Delete("/:flash_sale_id", handler.DeleteFlashSale, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+}
+
+func (m *moduleFactory) ReviewModule() {
+	repository := reviewsRepositories.ReviewsRepository(m.server.db)
+	usecase := reviewsUsecases.ReviewsUsecase(repository)
+	handler := reviewsHandlers.ReviewsHandler(m.server.cfg, usecase)
+
+	router := m.router.Group("/reviews")
+
+	m.router.Get("/products/:product_id/reviews", handler.FindProductReview, m.middlewares.OptionalJwtAuth())
+	m.router.Post("/products/:product_id/reviews", handler.InsertReview, m.middlewares.JwtAuth())
+
+	router.Get("/", handler.FindReview, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Patch("/:review_id", handler.ModerateReview, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+}
+
+func (m *moduleFactory) QuestionModule() {
+	repository := questionsRepositories.QuestionsRepository(m.server.db)
+	usecase := questionsUsecases.QuestionsUsecase(repository)
+	handler := questionsHandlers.QuestionsHandler(m.server.cfg, usecase)
+
+	router := m.router.Group("/questions")
+
+	m.router.Get("/products/:product_id/questions", handler.FindProductQuestion, m.middlewares.OptionalJwtAuth())
+	m.router.Post("/products/:product_id/questions", handler.InsertQuestion, m.middlewares.JwtAuth())
+
+	router.Get("/", handler.FindQuestion, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Patch("/:question_id", handler.AnswerQuestion, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+
+	router.Post("/:question_id/helpful", handler.VoteQuestion, m.middlewares.JwtAuth())
+	router.Delete("/:question_id/helpful", handler.UnvoteQuestion, m.middlewares.JwtAuth())
+}
+
+func (m *moduleFactory) RecommendationModule() {
+	repository := recommendationsRepositories.RecommendationsRepository(m.server.db)
+	usecase := recommendationsUsecases.RecommendationsUsecase(repository)
+	handler := recommendationsHandlers.RecommendationsHandler(m.server.cfg, usecase)
+
+	m.router.Get("/products/:product_id/related", handler.FindRelated)
+	m.router.Get("/products/:product_id/bought-together", handler.FindBoughtTogether)
+}
+
+func (m *moduleFactory) NotificationModule() {
+	repository := notificationsRepositories.NotificationsRepository(m.server.db)
+	usecase := notificationsUsecases.NotificationsUsecase(repository)
+	handler := notificationsHandlers.NotificationsHandler(m.server.cfg, usecase)
+
+	router := m.router.Group("/users/:user_id/notifications")
+
+	router.Get("/", handler.FindNotification, m.middlewares.JwtAuth(), m.middlewares.ParamsCheck())
+	router.Patch("/:notification_id", handler.ReadNotification, m.middlewares.JwtAuth(), m.middlewares.ParamsCheck())
+}
+
+func (m *moduleFactory) ReportModule() {
+	repository := reportsRepositories.ReportsRepository(m.server.db, m.server.cfg)
+	usecase := reportsUsecases.ReportsUsecase(repository)
+	handler := reportsHandlers.ReportsHandler(m.server.cfg, usecase)
 
-	router.Get("/", ordersHandler.FindOrder, m.middlewares.JwtAuth())
-	router.Get("/:user_id/:order_id", ordersHandler.FindOneOrder, m.middlewares.JwtAuth(), m.middlewares.ParamsCheck())
+	router := m.router.Group("/reports")
 
-	router.Patch("/:user_id/:order_id", ordersHandler.UpdateOrder, m.middlewares.JwtAuth(), m.middlewares.ParamsCheck())
+	router.Get("/revenue", handler.FindRevenue, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Get("/orders-status", handler.FindOrderStatus, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Get("/top-products", handler.FindTopProduct, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Get("/top-categories", handler.FindTopCategory, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Get("/average-order-value", handler.FindAverageOrderValue, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Get("/customers", handler.FindCustomer, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
+	router.Get("/prices", handler.FindPrice, m.middlewares.JwtAuth(), m.middlewares.Authorize(2))
 }
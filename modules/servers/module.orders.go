@@ -0,0 +1,60 @@
+package servers
+
+import (
+	"github.com/IzePhanthakarn/go-basic-shop/modules/files/filesUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersHandlers"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsRepositories"
+)
+
+type IOrderModule interface {
+	Init()
+	Repository() ordersRepositories.IOrdersRepository
+	Usecase() ordersUsecases.IOrdersUsecase
+	Handler() ordersHandlers.IOrdersHandler
+}
+
+type orderModule struct {
+	*moduleFactory
+	repository ordersRepositories.IOrdersRepository
+	usecase    ordersUsecases.IOrdersUsecase
+	handler    ordersHandlers.IOrdersHandler
+}
+
+func (m *moduleFactory) OrderModule() IOrderModule {
+	filesUsecase := filesUsecases.FileUsecase(m.server.cfg)
+	productsRepository := productsRepositories.ProductsRepository(m.server.db, m.server.cfg, filesUsecase)
+
+	ordersRepository := ordersRepositories.OrdersRepository(m.server.db, m.server.cfg)
+	ordersUsecase := ordersUsecases.OrderUsecase(ordersRepository, productsRepository)
+	ordersHandler := ordersHandlers.OrdersHandlers(m.server.cfg, ordersUsecase)
+
+	return &orderModule{
+		moduleFactory: m,
+		repository:    ordersRepository,
+		usecase:       ordersUsecase,
+		handler:       ordersHandler,
+	}
+}
+
+func (o *orderModule) Init() {
+	router := o.router.Group("/orders")
+
+	router.Post("/", o.handler.InsertOrder, o.middlewares.JwtAuth())
+
+	router.Get("/", o.handler.FindOrder, o.middlewares.JwtAuth())
+	o.router.Get("/users/:user_id/orders", o.handler.FindUserOrder, o.middlewares.JwtAuth(), o.middlewares.ParamsCheck())
+	router.Get("/:user_id/:order_id", o.handler.FindOneOrder, o.middlewares.JwtAuth(), o.middlewares.ParamsCheck())
+
+	router.Patch("/:user_id/:order_id", o.handler.UpdateOrder, o.middlewares.JwtAuth(), o.middlewares.ParamsCheck())
+
+	router.Get("/:user_id/:order_id/messages", o.handler.FindMessage, o.middlewares.JwtAuth(), o.middlewares.ParamsCheck())
+	router.Post("/:user_id/:order_id/messages", o.handler.InsertMessage, o.middlewares.JwtAuth(), o.middlewares.ParamsCheck())
+}
+
+func (o *orderModule) Repository() ordersRepositories.IOrdersRepository { return o.repository }
+
+func (o *orderModule) Usecase() ordersUsecases.IOrdersUsecase { return o.usecase }
+
+func (o *orderModule) Handler() ordersHandlers.IOrdersHandler { return o.handler }
@@ -5,6 +5,8 @@ import (
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsHandlers"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsRepositories"
 	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions/questionsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/questions/questionsUsecases"
 )
 
 type IProductsModule interface {
@@ -24,8 +26,10 @@ type productsModule struct {
 func (m *moduleFactory) ProductsModule() IProductsModule {
 	fileUsecase := filesUsecases.FileUsecase(m.server.cfg)
 	productsRepository := productsRepositories.ProductsRepository(m.server.db, m.server.cfg, fileUsecase)
-	productsUsecase := productsUsecases.ProductsUsecase(productsRepository)
-	productsHandler := productsHandlers.ProductsHandler(m.server.cfg, fileUsecase, productsUsecase)
+	productsUsecase := productsUsecases.ProductsUsecase(productsRepository, fileUsecase)
+	questionsRepository := questionsRepositories.QuestionsRepository(m.server.db)
+	questionsUsecase := questionsUsecases.QuestionsUsecase(questionsRepository)
+	productsHandler := productsHandlers.ProductsHandler(m.server.cfg, fileUsecase, productsUsecase, questionsUsecase)
 
 	return &productsModule{
 		moduleFactory: m,
@@ -38,13 +42,34 @@ func (m *moduleFactory) ProductsModule() IProductsModule {
 func (p *productsModule) Init() {
 	router := p.router.Group("/products")
 
-	router.Get("/", p.handler.FindProduct)
-	router.Get("/:product_id", p.handler.FindOneProduct)
+	router.Get("/", p.handler.FindProduct, p.middlewares.OptionalJwtAuth())
+	router.Get("/export", p.handler.ExportProduct, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Get("/by-slug/:slug", p.handler.FindProductBySlug, p.middlewares.OptionalJwtAuth())
+	router.Get("/:product_id", p.handler.FindOneProduct, p.middlewares.OptionalJwtAuth())
 
 	router.Post("/", p.handler.AddProduct, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Post("/import", p.handler.ImportProduct, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
 	router.Patch("/:product_id", p.handler.UpdateProduct, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
 
+	router.Post("/:product_id/categories", p.handler.AddCategory, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Delete("/:product_id/categories/:category_id", p.handler.RemoveCategory, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+
+	router.Post("/:product_id/images", p.handler.AddImage, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Put("/:product_id/images", p.handler.ReorderImage, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Patch("/:product_id/images/:image_id", p.handler.UpdateImage, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Delete("/:product_id/images/:image_id", p.handler.RemoveImage, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+
+	router.Get("/:product_id/stocks", p.handler.FindStock, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Post("/:product_id/stocks", p.handler.AdjustStock, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+
+	router.Get("/:product_id/prices", p.handler.FindPrice, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Post("/:product_id/prices", p.handler.SchedulePrice, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+
 	router.Delete("/:product_id", p.handler.DeleteProduct, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Post("/:product_id/restore", p.handler.RestoreProduct, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+
+	router.Post("/:product_id/preview-token", p.handler.RotatePreviewToken, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
+	router.Delete("/:product_id/preview-token", p.handler.RevokePreviewToken, p.middlewares.JwtAuth(), p.middlewares.Authorize(2))
 }
 
 func (f *productsModule) Repository() productsRepositories.IProductsRepository { return f.repository }
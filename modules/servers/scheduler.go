@@ -0,0 +1,50 @@
+package servers
+
+import (
+	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo/appinfoRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/appinfo/appinfoUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/files/filesUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/orders/ordersUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/products/productsUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations/recommendationsRepositories"
+	"github.com/IzePhanthakarn/go-basic-shop/modules/recommendations/recommendationsUsecases"
+	"github.com/IzePhanthakarn/go-basic-shop/pkg/scheduler"
+)
+
+func InitScheduler(s *server) scheduler.IScheduler {
+	jobs := scheduler.NewScheduler(s.db)
+
+	// Orders
+	filesUsecase := filesUsecases.FileUsecase(s.cfg)
+	productsRepository := productsRepositories.ProductsRepository(s.db, s.cfg, filesUsecase)
+	ordersRepository := ordersRepositories.OrdersRepository(s.db, s.cfg)
+	ordersUsecase := ordersUsecases.OrderUsecase(ordersRepository, productsRepository)
+
+	if s.cfg.Scheduler().OrderExpires() > 0 {
+		jobs.Register("orders-expire", s.cfg.Scheduler().Interval(), func() error {
+			return ordersUsecase.ExpireOrder(s.cfg.Scheduler().OrderExpires())
+		})
+	}
+
+	// Products
+	productsUsecase := productsUsecases.ProductsUsecase(productsRepository, filesUsecase)
+
+	jobs.Register("products-prices", s.cfg.Scheduler().Interval(), productsUsecase.ApplyScheduledPrice)
+
+	// Recommendations
+	recommendationsRepository := recommendationsRepositories.RecommendationsRepository(s.db)
+	recommendationsUsecase := recommendationsUsecases.RecommendationsUsecase(recommendationsRepository)
+
+	jobs.Register("products-recommendations", s.cfg.Scheduler().RecommendationsInterval(), recommendationsUsecase.RefreshRecommendation)
+
+	// Categories, the Thai slugs from before the transliteration are reset, the
+	// job finds nothing to do once they are
+	appinfoRepository := appinfoRepositories.AppinfoRepository(s.db)
+	appinfoUsecase := appinfoUsecases.AppinfoUsecase(appinfoRepository)
+
+	jobs.Register("categories-slugs", s.cfg.Scheduler().Interval(), appinfoUsecase.ResetCategorySlug)
+
+	return jobs
+}
@@ -56,17 +56,28 @@ func (s *server) Start() {
 	modules.AppinfoModule()
 	modules.FileModule().Init()
 	modules.ProductsModule().Init()
-	modules.OrderModule()
+	modules.OrderModule().Init()
+	modules.FlashSaleModule()
+	modules.ReviewModule()
+	modules.QuestionModule()
+	modules.RecommendationModule()
+	modules.NotificationModule()
+	modules.ReportModule()
 	modules.SwaggerModule()
 
 	s.app.Use(middlewares.RouterCheck())
 
+	// Schedulers
+	jobs := InitScheduler(s)
+	jobs.Start()
+
 	// Greaceful shutdown
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	go func() {
 		<-c
 		log.Println("server is shutting down...")
+		jobs.Stop()
 		_ = s.app.Shutdown()
 	}()
 
@@ -0,0 +1,40 @@
+package myTests
+
+import (
+	"testing"
+
+	"github.com/IzePhanthakarn/go-basic-shop/modules/orders"
+)
+
+type testUpdateOrder struct {
+	req    *orders.Order
+	expect string
+}
+
+func TestUpdateOrder(t *testing.T) {
+	tests := []testUpdateOrder{
+		{
+			req: &orders.Order{
+				Id:     "O000001",
+				UserId: "U000001",
+				Status: "completed",
+			},
+			expect: "status completed can only be set by an admin",
+		},
+		{
+			req: &orders.Order{
+				Id:     "O000001",
+				UserId: "U000001",
+				Status: "shipping",
+			},
+			expect: "status shipping can only be set by an admin",
+		},
+	}
+
+	ordersModule := SetupTest().OrderModule()
+	for _, test := range tests {
+		if _, err := ordersModule.Usecase().UpdateOrder(test.req); err == nil || err.Error() != test.expect {
+			t.Errorf("expect: %v, got: %v", test.expect, err)
+		}
+	}
+}
@@ -20,7 +20,7 @@ func TestFindOneProduct(t *testing.T) {
 		{
 			productId: "P000001",
 			isErr:     false,
-			expect:    `{"id":"P000001","title":"Coffee","description":"Just a food \u0026 beverage product","category":{"id":1,"title":"food \u0026 beverage"},"created_at":"2025-06-01T23:05:20.123876","updated_at":"2025-06-01T23:05:20.123876","price":150,"images":[{"id":"c580fe73-afb3-47d1-a9df-eed24fdaea9b","filename":"fb1_1.jpg","url":"https://i.pinimg.com/564x/4a/1c/4a/4a1c4a9755e4d3bdfcb45a1c3a58712f.jpg"},{"id":"43bcd3fa-6f7f-4251-b196-f30ad4ea625e","filename":"fb1_2.jpg","url":"https://i.pinimg.com/564x/4a/1c/4a/4a1c4a9755e4d3bdfcb45a1c3a58712f.jpg"},{"id":"77d9e690-b722-4039-b0fe-5f7d9af0e6b4","filename":"fb1_3.jpg","url":"https://i.pinimg.com/564x/4a/1c/4a/4a1c4a9755e4d3bdfcb45a1c3a58712f.jpg"}]}`,
+			expect:    `{"id":"P000001","title":"Coffee","slug":"coffee","description":"Just a food \u0026 beverage product","category":{"id":1,"title":"food \u0026 beverage","breadcrumbs":[{"id":1,"title":"food \u0026 beverage"}]},"categories":[{"id":1,"title":"food \u0026 beverage","breadcrumbs":[{"id":1,"title":"food \u0026 beverage"}]}],"created_at":"2025-06-01T23:05:20.123876","updated_at":"2025-06-01T23:05:20.123876","status":"active","published":true,"price":150,"in_stock":true,"available_qty":null,"rating":0,"review_count":0,"images":[{"id":"c580fe73-afb3-47d1-a9df-eed24fdaea9b","filename":"fb1_1.jpg","url":"https://i.pinimg.com/564x/4a/1c/4a/4a1c4a9755e4d3bdfcb45a1c3a58712f.jpg"},{"id":"43bcd3fa-6f7f-4251-b196-f30ad4ea625e","filename":"fb1_2.jpg","url":"https://i.pinimg.com/564x/4a/1c/4a/4a1c4a9755e4d3bdfcb45a1c3a58712f.jpg"},{"id":"77d9e690-b722-4039-b0fe-5f7d9af0e6b4","filename":"fb1_3.jpg","url":"https://i.pinimg.com/564x/4a/1c/4a/4a1c4a9755e4d3bdfcb45a1c3a58712f.jpg"}]}`,
 		},
 	}
 
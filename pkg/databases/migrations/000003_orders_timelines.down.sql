@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS set_updated_at_timestamp_notifications_table ON "notifications";
+
+DROP INDEX IF EXISTS "orders_status_created_at_idx";
+
+DROP TABLE IF EXISTS "notifications" CASCADE;
+DROP TABLE IF EXISTS "orders_timelines" CASCADE;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE "orders_timelines" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "order_id" VARCHAR NOT NULL,
+  "event" VARCHAR NOT NULL,
+  "message" VARCHAR NOT NULL DEFAULT '',
+  "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE "notifications" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" VARCHAR NOT NULL,
+  "title" VARCHAR NOT NULL,
+  "message" VARCHAR NOT NULL DEFAULT '',
+  "is_read" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+  "updated_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "orders_timelines" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+ALTER TABLE "notifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "orders_timelines_order_id_idx" ON "orders_timelines" ("order_id");
+CREATE INDEX "notifications_user_id_idx" ON "notifications" ("user_id");
+CREATE INDEX "orders_status_created_at_idx" ON "orders" ("status", "created_at");
+
+CREATE TRIGGER set_updated_at_timestamp_notifications_table BEFORE UPDATE ON "notifications" FOR EACH ROW EXECUTE PROCEDURE set_updated_at_column();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE "orders" DROP CONSTRAINT IF EXISTS "orders_number_key";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "number";
+
+DROP TABLE IF EXISTS "orders_numbers" CASCADE;
+
+COMMIT;
\ No newline at end of file
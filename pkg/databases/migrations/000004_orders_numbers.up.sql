@@ -0,0 +1,14 @@
+BEGIN;
+
+--Counter rows are locked by the order transaction, so numbers are gap-free
+CREATE TABLE "orders_numbers" (
+  "period" VARCHAR PRIMARY KEY,
+  "last_value" BIGINT NOT NULL DEFAULT 0
+);
+
+ALTER TABLE "orders" ADD COLUMN "number" VARCHAR;
+UPDATE "orders" SET "number" = "id";
+ALTER TABLE "orders" ALTER COLUMN "number" SET NOT NULL;
+ALTER TABLE "orders" ADD CONSTRAINT "orders_number_key" UNIQUE ("number");
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE "orders_messages" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "order_id" VARCHAR NOT NULL,
+  "user_id" VARCHAR NOT NULL,
+  "message" VARCHAR NOT NULL DEFAULT '',
+  "attachments" jsonb NOT NULL DEFAULT '[]'::jsonb,
+  "is_internal" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "orders_messages" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+ALTER TABLE "orders_messages" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "orders_messages_order_id_idx" ON "orders_messages" ("order_id");
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "products_stocks" CASCADE;
+
+ALTER TABLE "products" DROP CONSTRAINT IF EXISTS "products_stock_check";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "stock";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,25 @@
+BEGIN;
+
+--NULL stock isn't tracked, the products from before stock tracking never run out
+--until a stock adjustment starts tracking them
+ALTER TABLE "products" ADD COLUMN "stock" INT;
+ALTER TABLE "products" ADD CONSTRAINT "products_stock_check" CHECK ("stock" >= 0);
+
+--Every stock movement is recorded, qty is positive for stock in and negative for stock out
+CREATE TABLE "products_stocks" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "product_id" VARCHAR NOT NULL,
+  "qty" INT NOT NULL,
+  "reason" VARCHAR NOT NULL,
+  "order_id" VARCHAR,
+  "user_id" VARCHAR,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "products_stocks" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+ALTER TABLE "products_stocks" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE SET NULL;
+ALTER TABLE "products_stocks" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+CREATE INDEX "products_stocks_product_id_idx" ON "products_stocks" ("product_id");
+
+COMMIT;
\ No newline at end of file
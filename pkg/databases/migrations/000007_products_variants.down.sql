@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS set_stock_products_variants_table ON "products_variants";
+DROP FUNCTION IF EXISTS set_products_stock_column();
+
+ALTER TABLE "products_orders" DROP COLUMN IF EXISTS "variant";
+ALTER TABLE "products_stocks" DROP COLUMN IF EXISTS "variant_id";
+
+DROP TABLE IF EXISTS "products_variants" CASCADE;
+DROP TABLE IF EXISTS "products_options" CASCADE;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,61 @@
+BEGIN;
+
+CREATE TABLE "products_options" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "product_id" VARCHAR NOT NULL,
+  "name" VARCHAR NOT NULL,
+  "values" jsonb NOT NULL DEFAULT '[]'::jsonb,
+  "position" INT NOT NULL DEFAULT 0,
+  UNIQUE ("product_id", "name")
+);
+
+CREATE TABLE "products_variants" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "product_id" VARCHAR NOT NULL,
+  "sku" VARCHAR NOT NULL,
+  "options" jsonb NOT NULL DEFAULT '{}'::jsonb,
+  "price" FLOAT,
+  "stock" INT NOT NULL DEFAULT 0 CHECK ("stock" >= 0),
+  "image" jsonb,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+  "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+  "deleted_at" TIMESTAMP
+);
+
+ALTER TABLE "products_options" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+ALTER TABLE "products_variants" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "products_variants_product_id_idx" ON "products_variants" ("product_id");
+
+--A removed variant is archived (deleted_at), it keeps its stock ledger and frees its sku and options
+CREATE UNIQUE INDEX "products_variants_sku_key" ON "products_variants" ("sku") WHERE "deleted_at" IS NULL;
+CREATE UNIQUE INDEX "products_variants_options_key" ON "products_variants" ("product_id", "options") WHERE "deleted_at" IS NULL;
+
+ALTER TABLE "products_stocks" ADD COLUMN "variant_id" uuid;
+ALTER TABLE "products_stocks" ADD FOREIGN KEY ("variant_id") REFERENCES "products_variants" ("id");
+
+ALTER TABLE "products_orders" ADD COLUMN "variant" jsonb;
+
+--A product with variants keeps the sum of its variants stock, so listing and filtering stay on "products",
+--archived variants are left out and the stock is untracked (NULL) again when no variant is left
+CREATE OR REPLACE FUNCTION set_products_stock_column()
+RETURNS TRIGGER AS $$
+DECLARE
+    _product_id VARCHAR := COALESCE(NEW."product_id", OLD."product_id");
+BEGIN
+    UPDATE "products" SET
+        "stock" = (
+            SELECT CASE WHEN COUNT(*) = 0 THEN NULL ELSE SUM("stock") END
+            FROM "products_variants"
+            WHERE "product_id" = _product_id
+            AND "deleted_at" IS NULL
+        )
+    WHERE "id" = _product_id;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_updated_at_timestamp_products_variants_table BEFORE UPDATE ON "products_variants" FOR EACH ROW EXECUTE PROCEDURE set_updated_at_column();
+CREATE TRIGGER set_stock_products_variants_table AFTER INSERT OR DELETE OR UPDATE OF "stock", "deleted_at" ON "products_variants" FOR EACH ROW EXECUTE PROCEDURE set_products_stock_column();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "products_search_idx";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "search";
+
+COMMIT;
\ No newline at end of file
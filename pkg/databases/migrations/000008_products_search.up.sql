@@ -0,0 +1,11 @@
+BEGIN;
+
+--'simple' keeps words as they are, so Thai and English titles are matched the same way
+ALTER TABLE "products" ADD COLUMN "search" tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('simple', COALESCE("title", '')), 'A') ||
+  setweight(to_tsvector('simple', COALESCE("description", '')), 'B')
+) STORED;
+
+CREATE INDEX "products_search_idx" ON "products" USING GIN ("search");
+
+COMMIT;
\ No newline at end of file
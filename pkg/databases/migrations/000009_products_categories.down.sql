@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "products_categories_category_id_idx";
+ALTER TABLE "products_categories" DROP CONSTRAINT IF EXISTS "products_categories_product_id_category_id_key";
+ALTER TABLE "products_categories" DROP COLUMN IF EXISTS "created_at";
+
+COMMIT;
\ No newline at end of file
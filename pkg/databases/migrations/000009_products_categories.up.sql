@@ -0,0 +1,16 @@
+BEGIN;
+
+--The first category of a product is its main category
+ALTER TABLE "products_categories" ADD COLUMN "created_at" TIMESTAMP NOT NULL DEFAULT now();
+
+DELETE FROM "products_categories" "a"
+USING "products_categories" "b"
+WHERE "a"."product_id" = "b"."product_id"
+AND "a"."category_id" = "b"."category_id"
+AND "a"."id" > "b"."id";
+
+ALTER TABLE "products_categories" ADD CONSTRAINT "products_categories_product_id_category_id_key" UNIQUE ("product_id", "category_id");
+
+CREATE INDEX "products_categories_category_id_idx" ON "products_categories" ("category_id");
+
+COMMIT;
\ No newline at end of file
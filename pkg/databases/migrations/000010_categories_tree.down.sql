@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP VIEW IF EXISTS "categories_breadcrumbs";
+DROP TRIGGER IF EXISTS set_closure_categories_table ON "categories";
+DROP FUNCTION IF EXISTS set_categories_closure();
+DROP TABLE IF EXISTS "categories_closure" CASCADE;
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "parent_id";
+
+COMMIT;
\ No newline at end of file
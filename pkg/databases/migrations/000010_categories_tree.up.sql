@@ -0,0 +1,90 @@
+BEGIN;
+
+ALTER TABLE "categories" ADD COLUMN "parent_id" INT;
+ALTER TABLE "categories" ADD FOREIGN KEY ("parent_id") REFERENCES "categories" ("id") ON DELETE SET NULL;
+
+--Every category is linked to itself and to all of its ancestors
+CREATE TABLE "categories_closure" (
+  "ancestor_id" INT NOT NULL,
+  "descendant_id" INT NOT NULL,
+  "depth" INT NOT NULL,
+  PRIMARY KEY ("ancestor_id", "descendant_id")
+);
+
+ALTER TABLE "categories_closure" ADD FOREIGN KEY ("ancestor_id") REFERENCES "categories" ("id") ON DELETE CASCADE;
+ALTER TABLE "categories_closure" ADD FOREIGN KEY ("descendant_id") REFERENCES "categories" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "categories_closure_descendant_id_idx" ON "categories_closure" ("descendant_id");
+
+INSERT INTO "categories_closure" (
+  "ancestor_id",
+  "descendant_id",
+  "depth"
+)
+SELECT
+  "id",
+  "id",
+  0
+FROM "categories";
+
+CREATE OR REPLACE FUNCTION set_categories_closure()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO "categories_closure" ("ancestor_id", "descendant_id", "depth")
+        SELECT "ancestor_id", NEW."id", "depth" + 1
+        FROM "categories_closure"
+        WHERE "descendant_id" = NEW."parent_id"
+        UNION ALL
+        SELECT NEW."id", NEW."id", 0;
+        RETURN NULL;
+    END IF;
+
+    IF NEW."parent_id" IS NOT DISTINCT FROM OLD."parent_id" THEN
+        RETURN NULL;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1
+        FROM "categories_closure"
+        WHERE "ancestor_id" = NEW."id"
+        AND "descendant_id" = NEW."parent_id"
+    ) THEN
+        RAISE EXCEPTION 'category % can not be moved under its own descendant', NEW."id";
+    END IF;
+
+    --Unlink the subtree from the old ancestors, then link it to the new ones
+    DELETE FROM "categories_closure"
+    WHERE "descendant_id" IN (
+        SELECT "descendant_id" FROM "categories_closure" WHERE "ancestor_id" = NEW."id"
+    )
+    AND "ancestor_id" IN (
+        SELECT "ancestor_id" FROM "categories_closure" WHERE "descendant_id" = NEW."id" AND "ancestor_id" != NEW."id"
+    );
+
+    INSERT INTO "categories_closure" ("ancestor_id", "descendant_id", "depth")
+    SELECT "a"."ancestor_id", "d"."descendant_id", "a"."depth" + "d"."depth" + 1
+    FROM "categories_closure" "a"
+        CROSS JOIN "categories_closure" "d"
+    WHERE "a"."descendant_id" = NEW."parent_id"
+    AND "d"."ancestor_id" = NEW."id";
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_closure_categories_table AFTER INSERT OR UPDATE OF "parent_id" ON "categories" FOR EACH ROW EXECUTE PROCEDURE set_categories_closure();
+
+--Path from the root to the category
+CREATE VIEW "categories_breadcrumbs" AS
+SELECT
+  "cc"."descendant_id" AS "category_id",
+  json_agg(
+    json_build_object('id', "a"."id", 'title', "a"."title")
+    ORDER BY "cc"."depth" DESC
+  ) AS "breadcrumbs"
+FROM "categories_closure" "cc"
+  INNER JOIN "categories" "a" ON "a"."id" = "cc"."ancestor_id"
+GROUP BY "cc"."descendant_id";
+
+COMMIT;
\ No newline at end of file
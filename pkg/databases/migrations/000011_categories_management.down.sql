@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE "products_categories" DROP CONSTRAINT IF EXISTS "products_categories_category_id_fkey";
+ALTER TABLE "products_categories" ADD CONSTRAINT "products_categories_category_id_fkey" FOREIGN KEY ("category_id") REFERENCES "categories" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "categories" DROP CONSTRAINT IF EXISTS "categories_slug_key";
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "image";
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "position";
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "slug";
+
+COMMIT;
\ No newline at end of file
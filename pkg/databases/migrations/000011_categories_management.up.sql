@@ -0,0 +1,25 @@
+BEGIN;
+
+ALTER TABLE "categories" ADD COLUMN "slug" VARCHAR;
+ALTER TABLE "categories" ADD COLUMN "position" INT NOT NULL DEFAULT 0;
+ALTER TABLE "categories" ADD COLUMN "image" jsonb;
+
+UPDATE "categories" SET "slug" = COALESCE(NULLIF(TRIM(BOTH '-' FROM LOWER(regexp_replace("title", '[^[:alnum:]]+', '-', 'g'))), ''), 'category');
+
+--Titles that end up with the same slug keep their id as a suffix
+UPDATE "categories" "a" SET "slug" = CONCAT("a"."slug", '-', "a"."id")
+WHERE EXISTS (
+  SELECT 1
+  FROM "categories" "b"
+  WHERE "b"."slug" = "a"."slug"
+  AND "b"."id" < "a"."id"
+);
+
+ALTER TABLE "categories" ALTER COLUMN "slug" SET NOT NULL;
+ALTER TABLE "categories" ADD CONSTRAINT "categories_slug_key" UNIQUE ("slug");
+
+--A category that still has products can not be deleted, they have to be moved first
+ALTER TABLE "products_categories" DROP CONSTRAINT IF EXISTS "products_categories_category_id_fkey";
+ALTER TABLE "products_categories" ADD CONSTRAINT "products_categories_category_id_fkey" FOREIGN KEY ("category_id") REFERENCES "categories" ("id") ON DELETE RESTRICT;
+
+COMMIT;
\ No newline at end of file
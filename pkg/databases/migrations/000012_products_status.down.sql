@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS set_deleted_at_products_table ON "products";
+DROP FUNCTION IF EXISTS set_products_deleted_at_column();
+ALTER TABLE "products" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "status";
+DROP TYPE IF EXISTS "product_status";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TYPE "product_status" AS ENUM (
+  'draft',
+  'active',
+  'archived'
+);
+
+ALTER TABLE "products" ADD COLUMN "status" product_status NOT NULL DEFAULT 'active';
+ALTER TABLE "products" ADD COLUMN "deleted_at" TIMESTAMP;
+
+CREATE INDEX "products_status_idx" ON "products" ("status");
+
+--deleted_at follows the status, it is the time the product was archived
+CREATE OR REPLACE FUNCTION set_products_deleted_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW."status" != 'archived' THEN
+        NEW."deleted_at" = NULL;
+    ELSIF TG_OP = 'INSERT' OR OLD."status" != 'archived' THEN
+        NEW."deleted_at" = now();
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_deleted_at_products_table BEFORE INSERT OR UPDATE OF "status" ON "products" FOR EACH ROW EXECUTE PROCEDURE set_products_deleted_at_column();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "preview_token";
+ALTER TABLE "products" DROP CONSTRAINT IF EXISTS "products_publish_window_check";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "unpublish_at";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "publish_at";
+
+COMMIT;
\ No newline at end of file
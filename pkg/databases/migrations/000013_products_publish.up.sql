@@ -0,0 +1,11 @@
+BEGIN;
+
+--Customers see an active product between publish_at and unpublish_at, null means no limit
+ALTER TABLE "products" ADD COLUMN "publish_at" TIMESTAMP;
+ALTER TABLE "products" ADD COLUMN "unpublish_at" TIMESTAMP;
+ALTER TABLE "products" ADD CONSTRAINT "products_publish_window_check" CHECK ("publish_at" IS NULL OR "unpublish_at" IS NULL OR "unpublish_at" > "publish_at");
+
+--Anyone with the token can see the product before it is published
+ALTER TABLE "products" ADD COLUMN "preview_token" uuid UNIQUE;
+
+COMMIT;
\ No newline at end of file
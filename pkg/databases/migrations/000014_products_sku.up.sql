@@ -0,0 +1,6 @@
+BEGIN;
+
+--Imports match products by sku when they have no id
+ALTER TABLE "products" ADD COLUMN "sku" VARCHAR UNIQUE;
+
+COMMIT;
\ No newline at end of file
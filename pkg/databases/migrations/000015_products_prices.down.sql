@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS set_prices_history_products_table ON "products";
+DROP FUNCTION IF EXISTS set_products_prices_history();
+DROP TRIGGER IF EXISTS set_effective_to_products_prices_table ON "products_prices";
+DROP FUNCTION IF EXISTS set_products_prices_effective_to();
+DROP TABLE IF EXISTS "products_prices" CASCADE;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,82 @@
+BEGIN;
+
+--A price is in effect from effective_from until the next price of the product, effective_to follows it
+CREATE TABLE "products_prices" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "product_id" VARCHAR NOT NULL,
+  "price" FLOAT NOT NULL CHECK ("price" >= 0),
+  "compare_at_price" FLOAT CHECK ("compare_at_price" >= 0),
+  "effective_from" TIMESTAMP NOT NULL DEFAULT now(),
+  "effective_to" TIMESTAMP,
+  "user_id" VARCHAR,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE ("product_id", "effective_from")
+);
+
+ALTER TABLE "products_prices" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+ALTER TABLE "products_prices" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+INSERT INTO "products_prices" (
+  "product_id",
+  "price",
+  "effective_from"
+)
+SELECT
+  "id",
+  "price",
+  "created_at"
+FROM "products";
+
+CREATE OR REPLACE FUNCTION set_products_prices_effective_to()
+RETURNS TRIGGER AS $$
+DECLARE
+    product VARCHAR := COALESCE(NEW."product_id", OLD."product_id");
+BEGIN
+    UPDATE "products_prices" "pp"
+    SET "effective_to" = "n"."next_from"
+    FROM (
+        SELECT
+            "id",
+            LEAD("effective_from") OVER (ORDER BY "effective_from") AS "next_from"
+        FROM "products_prices"
+        WHERE "product_id" = product
+    ) "n"
+    WHERE "pp"."id" = "n"."id"
+    AND "pp"."effective_to" IS DISTINCT FROM "n"."next_from";
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_effective_to_products_prices_table AFTER INSERT OR DELETE OR UPDATE OF "effective_from" ON "products_prices" FOR EACH ROW EXECUTE PROCEDURE set_products_prices_effective_to();
+
+--A price that is set on the product right away is kept in the history too,
+--unless it is the price that is already in effect
+CREATE OR REPLACE FUNCTION set_products_prices_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW."price" = OLD."price" THEN
+        RETURN NULL;
+    END IF;
+
+    IF NEW."price" = (
+        SELECT "price"
+        FROM "products_prices"
+        WHERE "product_id" = NEW."id"
+        AND "effective_from" <= now()
+        ORDER BY "effective_from" DESC
+        LIMIT 1
+    ) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO "products_prices" ("product_id", "price", "effective_from")
+    VALUES (NEW."id", NEW."price", now())
+    ON CONFLICT ("product_id", "effective_from") DO UPDATE
+    SET "price" = EXCLUDED."price", "compare_at_price" = NULL;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_prices_history_products_table AFTER INSERT OR UPDATE OF "price" ON "products" FOR EACH ROW EXECUTE PROCEDURE set_products_prices_history();
+
+COMMIT;
\ No newline at end of file
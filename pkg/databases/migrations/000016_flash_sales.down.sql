@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS release_flash_sales_orders_table ON "orders";
+DROP FUNCTION IF EXISTS release_flash_sales_orders();
+DROP TABLE IF EXISTS "flash_sales_orders" CASCADE;
+DROP TABLE IF EXISTS "flash_sales_products" CASCADE;
+DROP TABLE IF EXISTS "flash_sales" CASCADE;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,73 @@
+BEGIN;
+
+CREATE TABLE "flash_sales" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "title" VARCHAR NOT NULL,
+  "start_at" TIMESTAMP NOT NULL,
+  "end_at" TIMESTAMP NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+  "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+  CHECK ("end_at" > "start_at")
+);
+
+--sold_qty is reserved by orders and can never pass the qty cap
+CREATE TABLE "flash_sales_products" (
+  "flash_sale_id" uuid NOT NULL,
+  "product_id" VARCHAR NOT NULL,
+  "price" FLOAT NOT NULL CHECK ("price" >= 0),
+  "qty" INT NOT NULL CHECK ("qty" > 0),
+  "sold_qty" INT NOT NULL DEFAULT 0 CHECK ("sold_qty" >= 0 AND "sold_qty" <= "qty"),
+  "per_customer_limit" INT CHECK ("per_customer_limit" > 0),
+  PRIMARY KEY ("flash_sale_id", "product_id")
+);
+
+--What each order has reserved, it is used for the per customer limit and is
+--given back when the order is canceled
+CREATE TABLE "flash_sales_orders" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "flash_sale_id" uuid NOT NULL,
+  "product_id" VARCHAR NOT NULL,
+  "order_id" VARCHAR NOT NULL,
+  "user_id" VARCHAR NOT NULL,
+  "qty" INT NOT NULL CHECK ("qty" > 0),
+  "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX "flash_sales_products_product_id_idx" ON "flash_sales_products" ("product_id");
+CREATE INDEX "flash_sales_orders_customer_idx" ON "flash_sales_orders" ("flash_sale_id", "product_id", "user_id");
+CREATE INDEX "flash_sales_orders_order_id_idx" ON "flash_sales_orders" ("order_id");
+
+ALTER TABLE "flash_sales_products" ADD FOREIGN KEY ("flash_sale_id") REFERENCES "flash_sales" ("id") ON DELETE CASCADE;
+ALTER TABLE "flash_sales_products" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+ALTER TABLE "flash_sales_orders" ADD FOREIGN KEY ("flash_sale_id", "product_id") REFERENCES "flash_sales_products" ("flash_sale_id", "product_id") ON DELETE CASCADE;
+ALTER TABLE "flash_sales_orders" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+ALTER TABLE "flash_sales_orders" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE TRIGGER set_updated_at_timestamp_flash_sales_table BEFORE UPDATE ON "flash_sales" FOR EACH ROW EXECUTE PROCEDURE set_updated_at_column();
+
+--A canceled order gives its flash sale quantity back, whichever way it was canceled
+CREATE OR REPLACE FUNCTION release_flash_sales_orders()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE "flash_sales_products" "fp"
+    SET "sold_qty" = "fp"."sold_qty" - "r"."qty"
+    FROM (
+        SELECT
+            "flash_sale_id",
+            "product_id",
+            SUM("qty") AS "qty"
+        FROM "flash_sales_orders"
+        WHERE "order_id" = NEW."id"
+        GROUP BY "flash_sale_id", "product_id"
+    ) "r"
+    WHERE "fp"."flash_sale_id" = "r"."flash_sale_id"
+    AND "fp"."product_id" = "r"."product_id";
+
+    DELETE FROM "flash_sales_orders" WHERE "order_id" = NEW."id";
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER release_flash_sales_orders_table AFTER UPDATE OF "status" ON "orders" FOR EACH ROW WHEN (NEW."status" = 'canceled' AND OLD."status" != 'canceled') EXECUTE PROCEDURE release_flash_sales_orders();
+
+COMMIT;
\ No newline at end of file
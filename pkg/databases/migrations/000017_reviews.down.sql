@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS set_rating_reviews_table ON "reviews";
+DROP FUNCTION IF EXISTS set_products_rating();
+DROP INDEX IF EXISTS "products_rating_idx";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "review_count";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "rating";
+DROP TABLE IF EXISTS "reviews" CASCADE;
+DROP TYPE IF EXISTS "review_status";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,62 @@
+BEGIN;
+
+CREATE TYPE "review_status" AS ENUM (
+    'pending',
+    'approved',
+    'hidden'
+);
+
+CREATE TABLE "reviews" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "product_id" VARCHAR NOT NULL,
+  "user_id" VARCHAR NOT NULL,
+  "rating" INT NOT NULL CHECK ("rating" BETWEEN 1 AND 5),
+  "message" VARCHAR NOT NULL DEFAULT '',
+  "images" jsonb NOT NULL DEFAULT '[]'::jsonb,
+  "status" review_status NOT NULL DEFAULT 'pending',
+  "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+  "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE ("product_id", "user_id")
+);
+
+CREATE INDEX "reviews_product_id_status_idx" ON "reviews" ("product_id", "status", "created_at");
+CREATE INDEX "reviews_status_idx" ON "reviews" ("status", "created_at");
+
+ALTER TABLE "reviews" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+ALTER TABLE "reviews" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE TRIGGER set_updated_at_timestamp_reviews_table BEFORE UPDATE ON "reviews" FOR EACH ROW EXECUTE PROCEDURE set_updated_at_column();
+
+--The rating of a product is the average of its approved reviews, it is kept on
+--the product so products can be sorted and filtered by it
+ALTER TABLE "products" ADD COLUMN "rating" FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE "products" ADD COLUMN "review_count" INT NOT NULL DEFAULT 0;
+
+CREATE INDEX "products_rating_idx" ON "products" ("rating");
+
+CREATE OR REPLACE FUNCTION set_products_rating()
+RETURNS TRIGGER AS $$
+DECLARE
+    product VARCHAR := COALESCE(NEW."product_id", OLD."product_id");
+BEGIN
+    UPDATE "products" "p"
+    SET
+        "rating" = "r"."rating",
+        "review_count" = "r"."review_count"
+    FROM (
+        SELECT
+            COALESCE(ROUND(AVG("rating")::NUMERIC, 2), 0)::FLOAT AS "rating",
+            COUNT(*) AS "review_count"
+        FROM "reviews"
+        WHERE "product_id" = product
+        AND "status" = 'approved'
+    ) "r"
+    WHERE "p"."id" = product
+    AND ("p"."rating", "p"."review_count") IS DISTINCT FROM ("r"."rating", "r"."review_count");
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_rating_reviews_table AFTER INSERT OR DELETE OR UPDATE OF "rating", "status" ON "reviews" FOR EACH ROW EXECUTE PROCEDURE set_products_rating();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "questions_votes" CASCADE;
+DROP TABLE IF EXISTS "questions" CASCADE;
+
+COMMIT;
\ No newline at end of file
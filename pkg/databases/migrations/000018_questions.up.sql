@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TABLE "questions" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "product_id" VARCHAR NOT NULL,
+  "user_id" VARCHAR NOT NULL,
+  "question" VARCHAR NOT NULL,
+  "answer" VARCHAR,
+  "answered_by" VARCHAR,
+  "answered_at" TIMESTAMP,
+  "is_hidden" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+  "updated_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+--A user finds a question helpful once
+CREATE TABLE "questions_votes" (
+  "question_id" uuid NOT NULL,
+  "user_id" VARCHAR NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY ("question_id", "user_id")
+);
+
+ALTER TABLE "questions" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+ALTER TABLE "questions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "questions" ADD FOREIGN KEY ("answered_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+ALTER TABLE "questions_votes" ADD FOREIGN KEY ("question_id") REFERENCES "questions" ("id") ON DELETE CASCADE;
+ALTER TABLE "questions_votes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "questions_product_id_idx" ON "questions" ("product_id", "answered_at");
+CREATE INDEX "questions_unanswered_idx" ON "questions" ("created_at") WHERE "answer" IS NULL;
+
+CREATE TRIGGER set_updated_at_timestamp_questions_table BEFORE UPDATE ON "questions" FOR EACH ROW EXECUTE PROCEDURE set_updated_at_column();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW IF EXISTS "products_recommendations";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,80 @@
+BEGIN;
+
+--The recommendations are cached here and refreshed by the scheduler.
+--bought_together scores the share of the product's completed orders that had
+--the other product too, related adds the category similarity (Jaccard) to it.
+CREATE MATERIALIZED VIEW "products_recommendations" AS
+WITH "bought" AS (
+  SELECT DISTINCT
+    "po"."order_id",
+    "po"."product"->>'id' AS "product_id"
+  FROM "products_orders" "po"
+    INNER JOIN "orders" "o" ON "o"."id" = "po"."order_id"
+  WHERE "o"."status" = 'completed'
+), "orders_count" AS (
+  SELECT
+    "product_id",
+    COUNT(*) AS "orders"
+  FROM "bought"
+  GROUP BY "product_id"
+), "co_bought" AS (
+  SELECT
+    "a"."product_id",
+    "b"."product_id" AS "recommended_id",
+    COUNT(*)::FLOAT / MAX("oc"."orders") AS "score"
+  FROM "bought" "a"
+    INNER JOIN "bought" "b" ON "b"."order_id" = "a"."order_id" AND "b"."product_id" != "a"."product_id"
+    INNER JOIN "orders_count" "oc" ON "oc"."product_id" = "a"."product_id"
+  GROUP BY "a"."product_id", "b"."product_id"
+), "categories_count" AS (
+  SELECT
+    "product_id",
+    COUNT(*) AS "categories"
+  FROM "products_categories"
+  GROUP BY "product_id"
+), "similar" AS (
+  SELECT
+    "a"."product_id",
+    "b"."product_id" AS "recommended_id",
+    COUNT(*)::FLOAT / (MAX("ca"."categories") + MAX("cb"."categories") - COUNT(*)) AS "score"
+  FROM "products_categories" "a"
+    INNER JOIN "products_categories" "b" ON "b"."category_id" = "a"."category_id" AND "b"."product_id" != "a"."product_id"
+    INNER JOIN "categories_count" "ca" ON "ca"."product_id" = "a"."product_id"
+    INNER JOIN "categories_count" "cb" ON "cb"."product_id" = "b"."product_id"
+  GROUP BY "a"."product_id", "b"."product_id"
+), "scored" AS (
+  SELECT
+    'bought_together'::VARCHAR AS "kind",
+    "c"."product_id",
+    "c"."recommended_id",
+    "c"."score"
+  FROM "co_bought" "c"
+  UNION ALL
+  SELECT
+    'related'::VARCHAR AS "kind",
+    COALESCE("s"."product_id", "c"."product_id"),
+    COALESCE("s"."recommended_id", "c"."recommended_id"),
+    COALESCE("s"."score", 0) + COALESCE("c"."score", 0)
+  FROM "similar" "s"
+    FULL JOIN "co_bought" "c" ON "c"."product_id" = "s"."product_id" AND "c"."recommended_id" = "s"."recommended_id"
+), "ranked" AS (
+  SELECT
+    "s".*,
+    ROW_NUMBER() OVER (PARTITION BY "s"."kind", "s"."product_id" ORDER BY "s"."score" DESC, "s"."recommended_id") AS "rank"
+  FROM "scored" "s"
+    INNER JOIN "products" "p" ON "p"."id" = "s"."product_id"
+    INNER JOIN "products" "r" ON "r"."id" = "s"."recommended_id"
+)
+SELECT
+  "kind",
+  "product_id",
+  "recommended_id",
+  "score",
+  "rank"
+FROM "ranked"
+WHERE "rank" <= 20;
+
+--Refreshing concurrently needs a unique index
+CREATE UNIQUE INDEX "products_recommendations_idx" ON "products_recommendations" ("product_id", "kind", "recommended_id");
+
+COMMIT;
\ No newline at end of file
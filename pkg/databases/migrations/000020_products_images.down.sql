@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS "images_product_id_idx";
+ALTER TABLE "images" DROP COLUMN IF EXISTS "position";
+ALTER TABLE "images" DROP COLUMN IF EXISTS "alt";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE "images" ADD COLUMN "alt" VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE "images" ADD COLUMN "position" INT NOT NULL DEFAULT 0;
+
+--Images were listed in the order they were inserted, the first one is the primary image
+UPDATE "images" "i" SET
+  "position" = "o"."position"
+FROM (
+  SELECT
+    "id",
+    row_number() OVER (PARTITION BY "product_id" ORDER BY "created_at", ctid) - 1 AS "position"
+  FROM "images"
+) AS "o"
+WHERE "o"."id" = "i"."id";
+
+CREATE INDEX "images_product_id_idx" ON "images" ("product_id", "position");
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS set_slugs_products_table ON "products";
+DROP TRIGGER IF EXISTS set_slugs_categories_table ON "categories";
+DROP FUNCTION IF EXISTS set_products_slugs;
+DROP FUNCTION IF EXISTS set_categories_slugs;
+DROP TABLE IF EXISTS "products_slugs" CASCADE;
+DROP TABLE IF EXISTS "categories_slugs" CASCADE;
+ALTER TABLE "products" DROP CONSTRAINT IF EXISTS "products_slug_key";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "slug";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,72 @@
+BEGIN;
+
+ALTER TABLE "products" ADD COLUMN "slug" VARCHAR;
+
+--Only Latin titles can be slugified here, the others start from the product id
+UPDATE "products" SET "slug" = COALESCE(NULLIF(TRIM(BOTH '-' FROM regexp_replace(LOWER("title"), '[^a-z0-9]+', '-', 'g')), ''), LOWER("id"));
+
+--Titles that end up with the same slug keep their id as a suffix
+UPDATE "products" "a" SET "slug" = CONCAT("a"."slug", '-', LOWER("a"."id"))
+WHERE EXISTS (
+  SELECT 1
+  FROM "products" "b"
+  WHERE "b"."slug" = "a"."slug"
+  AND "b"."id" < "a"."id"
+);
+
+ALTER TABLE "products" ALTER COLUMN "slug" SET NOT NULL;
+ALTER TABLE "products" ADD CONSTRAINT "products_slug_key" UNIQUE ("slug");
+
+--Old slugs keep pointing to their product or category, links to them are redirected
+CREATE TABLE "products_slugs" (
+  "slug" VARCHAR NOT NULL PRIMARY KEY,
+  "product_id" VARCHAR NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE "categories_slugs" (
+  "slug" VARCHAR NOT NULL PRIMARY KEY,
+  "category_id" INT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "products_slugs" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+ALTER TABLE "categories_slugs" ADD FOREIGN KEY ("category_id") REFERENCES "categories" ("id") ON DELETE CASCADE;
+
+--The current slug wins over an old one, a slug that is taken again leaves the history
+CREATE OR REPLACE FUNCTION set_products_slugs()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD."slug" = NEW."slug" THEN
+        RETURN NULL;
+    END IF;
+    DELETE FROM "products_slugs" WHERE "slug" = NEW."slug";
+    IF TG_OP = 'UPDATE' THEN
+        INSERT INTO "products_slugs" ("slug", "product_id")
+        VALUES (OLD."slug", OLD."id")
+        ON CONFLICT ("slug") DO UPDATE SET "product_id" = EXCLUDED."product_id", "created_at" = now();
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION set_categories_slugs()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD."slug" = NEW."slug" THEN
+        RETURN NULL;
+    END IF;
+    DELETE FROM "categories_slugs" WHERE "slug" = NEW."slug";
+    IF TG_OP = 'UPDATE' THEN
+        INSERT INTO "categories_slugs" ("slug", "category_id")
+        VALUES (OLD."slug", OLD."id")
+        ON CONFLICT ("slug") DO UPDATE SET "category_id" = EXCLUDED."category_id", "created_at" = now();
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_slugs_products_table AFTER INSERT OR UPDATE OF "slug" ON "products" FOR EACH ROW EXECUTE PROCEDURE set_products_slugs();
+CREATE TRIGGER set_slugs_categories_table AFTER INSERT OR UPDATE OF "slug" ON "categories" FOR EACH ROW EXECUTE PROCEDURE set_categories_slugs();
+
+COMMIT;
\ No newline at end of file
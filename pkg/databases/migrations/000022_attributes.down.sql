@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "attributes";
+DROP TABLE IF EXISTS "categories_attributes" CASCADE;
+DROP TYPE IF EXISTS "attribute_type";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE TYPE "attribute_type" AS ENUM (
+  'text',
+  'number',
+  'boolean',
+  'select'
+);
+
+--Attributes of a category apply to the products of its descendants too
+CREATE TABLE "categories_attributes" (
+  "id" uuid NOT NULL UNIQUE PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "category_id" INT NOT NULL,
+  "name" VARCHAR NOT NULL,
+  "title" VARCHAR NOT NULL,
+  "type" attribute_type NOT NULL,
+  "values" jsonb NOT NULL DEFAULT '[]',
+  "unit" VARCHAR NOT NULL DEFAULT '',
+  "is_required" BOOLEAN NOT NULL DEFAULT FALSE,
+  "position" INT NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+  "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE ("category_id", "name")
+);
+
+ALTER TABLE "categories_attributes" ADD FOREIGN KEY ("category_id") REFERENCES "categories" ("id") ON DELETE CASCADE;
+
+CREATE TRIGGER set_updated_at_timestamp_categories_attributes_table BEFORE UPDATE ON "categories_attributes" FOR EACH ROW EXECUTE PROCEDURE set_updated_at_column();
+
+--Values are keyed by the attribute name, e.g. {"roast": "dark", "weight": 250}
+ALTER TABLE "products" ADD COLUMN "attributes" jsonb NOT NULL DEFAULT '{}';
+
+COMMIT;
\ No newline at end of file
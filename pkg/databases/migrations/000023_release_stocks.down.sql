@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS release_products_stocks_orders_table ON "orders";
+DROP FUNCTION IF EXISTS release_products_stocks();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,51 @@
+BEGIN;
+
+--A canceled order gives its reserved stock back, whichever way it was canceled,
+--like its flash sale quantity. The amount comes from the stock ledger so orders
+--placed before stock tracking are skipped, an untracked (NULL) stock stays NULL
+CREATE OR REPLACE FUNCTION release_products_stocks()
+RETURNS TRIGGER AS $$
+BEGIN
+    WITH "reserved" AS (
+        SELECT
+            "ps"."product_id",
+            "ps"."variant_id",
+            -SUM("ps"."qty") AS "qty"
+        FROM "products_stocks" "ps"
+        WHERE "ps"."order_id" = NEW."id"
+        GROUP BY "ps"."product_id", "ps"."variant_id"
+        HAVING SUM("ps"."qty") < 0
+    ), "restocked" AS (
+        UPDATE "products" "p"
+        SET "stock" = "p"."stock" + "r"."qty"
+        FROM "reserved" "r"
+        WHERE "p"."id" = "r"."product_id"
+        AND "r"."variant_id" IS NULL
+    ), "restocked_variants" AS (
+        UPDATE "products_variants" "v"
+        SET "stock" = "v"."stock" + "r"."qty"
+        FROM "reserved" "r"
+        WHERE "v"."id" = "r"."variant_id"
+    )
+    INSERT INTO "products_stocks" (
+        "product_id",
+        "variant_id",
+        "qty",
+        "reason",
+        "order_id"
+    )
+    SELECT
+        "r"."product_id",
+        "r"."variant_id",
+        "r"."qty",
+        'order canceled',
+        NEW."id"
+    FROM "reserved" "r";
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER release_products_stocks_orders_table AFTER UPDATE OF "status" ON "orders" FOR EACH ROW WHEN (NEW."status" = 'canceled' AND OLD."status" != 'canceled') EXECUTE PROCEDURE release_products_stocks();
+
+COMMIT;
\ No newline at end of file
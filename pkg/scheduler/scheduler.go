@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type JobFunc func() error
+
+type IScheduler interface {
+	Register(name string, interval time.Duration, fn JobFunc)
+	Start()
+	Stop()
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+}
+
+type scheduler struct {
+	db     *sqlx.DB
+	jobs   []*job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(db *sqlx.DB) IScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &scheduler{
+		db:     db,
+		jobs:   make([]*job, 0),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (s *scheduler) Register(name string, interval time.Duration, fn JobFunc) {
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		interval: interval,
+		fn:       fn,
+	})
+}
+
+func (s *scheduler) Start() {
+	for _, j := range s.jobs {
+		if j.interval <= 0 {
+			log.Printf("scheduler: %s is disabled", j.name)
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(j *job) {
+			defer s.wg.Done()
+
+			ticker := time.NewTicker(j.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-s.ctx.Done():
+					return
+				case <-ticker.C:
+					s.run(j)
+				}
+			}
+		}(j)
+	}
+}
+
+func (s *scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run executes a job only on the replica that holds the job's advisory lock,
+// other replicas skip the tick.
+func (s *scheduler) run(j *job) {
+	ctx, cancel := context.WithTimeout(s.ctx, j.interval)
+	defer cancel()
+
+	// Advisory locks belong to a session, so lock and unlock on the same connection
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		log.Printf("scheduler: %s connect failed: %v", j.name, err)
+		return
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.GetContext(ctx, &locked, `SELECT pg_try_advisory_lock(hashtext($1));`, j.name); err != nil {
+		log.Printf("scheduler: %s lock failed: %v", j.name, err)
+		return
+	}
+	if !locked {
+		return
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1));`, j.name)
+
+	if err := j.fn(); err != nil {
+		log.Printf("scheduler: %s failed: %v", j.name, err)
+	}
+}
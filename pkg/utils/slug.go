@@ -0,0 +1,327 @@
+package utils
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// latinLetters are the Latin letters that don't decompose into a letter and
+// its accents
+var latinLetters = strings.NewReplacer("ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "đ", "d", "ł", "l", "ı", "i")
+
+// Slugify lowercases s and joins its ASCII letters and digits with dashes.
+// Thai is transliterated to Latin letters first and accents are dropped, e.g.
+// Café -> cafe, other scripts become dashes.
+func Slugify(s string) string {
+	s = norm.NFKD.String(latinLetters.Replace(strings.ToLower(TransliterateThai(s))))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		case unicode.Is(unicode.Mn, r):
+			// The accent of the letter before it
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// UniqueSlug returns slug, or slug with the first free number suffix when it
+// is taken, e.g. coffee-2. A slug that ends with a number keeps it, iphone-1
+// becomes iphone-1-2.
+func UniqueSlug(slug string, taken []string) string {
+	unique := slug
+	for n := 2; slices.Contains(taken, unique); n++ {
+		unique = slug + "-" + strconv.Itoa(n)
+	}
+	return unique
+}
+
+// thaiConsonants are the RTGS sounds of a consonant that starts and that ends
+// a syllable
+var thaiConsonants = map[rune][2]string{
+	'ก': {"k", "k"}, 'ข': {"kh", "k"}, 'ฃ': {"kh", "k"}, 'ค': {"kh", "k"}, 'ฅ': {"kh", "k"}, 'ฆ': {"kh", "k"},
+	'ง': {"ng", "ng"}, 'จ': {"ch", "t"}, 'ฉ': {"ch", "t"}, 'ช': {"ch", "t"}, 'ซ': {"s", "t"}, 'ฌ': {"ch", "t"},
+	'ญ': {"y", "n"}, 'ฎ': {"d", "t"}, 'ฏ': {"t", "t"}, 'ฐ': {"th", "t"}, 'ฑ': {"th", "t"}, 'ฒ': {"th", "t"},
+	'ณ': {"n", "n"}, 'ด': {"d", "t"}, 'ต': {"t", "t"}, 'ถ': {"th", "t"}, 'ท': {"th", "t"}, 'ธ': {"th", "t"},
+	'น': {"n", "n"}, 'บ': {"b", "p"}, 'ป': {"p", "p"}, 'ผ': {"ph", "p"}, 'ฝ': {"f", "p"}, 'พ': {"ph", "p"},
+	'ฟ': {"f", "p"}, 'ภ': {"ph", "p"}, 'ม': {"m", "m"}, 'ย': {"y", "i"}, 'ร': {"r", "n"}, 'ล': {"l", "n"},
+	'ว': {"w", "o"}, 'ศ': {"s", "t"}, 'ษ': {"s", "t"}, 'ส': {"s", "t"}, 'ห': {"h", ""}, 'ฬ': {"l", "n"},
+	'อ': {"", ""}, 'ฮ': {"h", ""},
+}
+
+func isThaiConsonant(r rune) bool {
+	_, ok := thaiConsonants[r]
+	return ok
+}
+
+// isThaiCluster tells whether c and n are read together, like khr and pl
+func isThaiCluster(c, n rune) bool {
+	switch n {
+	case 'ร':
+		return strings.ContainsRune("กขคตปพ", c)
+	case 'ล':
+		return strings.ContainsRune("กขคปพ", c)
+	case 'ว':
+		return strings.ContainsRune("กขค", c)
+	}
+	return false
+}
+
+// isThaiVowel tells the vowel signs that follow, or sit above or below, a consonant
+func isThaiVowel(r rune) bool {
+	return (r >= 'ะ' && r <= 'ู') || r == '็' || r == 'ๅ'
+}
+
+// isThaiMark tells the tone marks and signs that have no sound of their own
+func isThaiMark(r rune) bool {
+	return (r >= '่' && r <= '๎') || r == 'ฺ'
+}
+
+// TransliterateThai writes Thai in Latin letters after the Royal Thai General
+// System. Thai leaves most vowels unwritten, so they are guessed and some words
+// come out differently from their official spelling.
+func TransliterateThai(s string) string {
+	if !strings.ContainsFunc(s, func(r rune) bool { return unicode.Is(unicode.Thai, r) }) {
+		return s
+	}
+
+	t := &thaiTransliterator{}
+	for _, r := range s {
+		// The thanthakhat silences the consonant it sits on, with its vowel
+		if r == '์' {
+			for len(t.rs) > 0 && (isThaiVowel(t.rs[len(t.rs)-1]) || isThaiMark(t.rs[len(t.rs)-1])) {
+				t.rs = t.rs[:len(t.rs)-1]
+			}
+			if len(t.rs) > 0 && isThaiConsonant(t.rs[len(t.rs)-1]) {
+				t.rs = t.rs[:len(t.rs)-1]
+			}
+			continue
+		}
+		t.rs = append(t.rs, r)
+	}
+
+	for t.i < len(t.rs) {
+		r := t.rs[t.i]
+		switch {
+		case isThaiConsonant(r) || (r >= 'เ' && r <= 'ไ'):
+			t.syllable()
+		case r >= '๐' && r <= '๙':
+			t.b.WriteRune('0' + r - '๐')
+			t.i++
+		case r == 'ฤ':
+			t.b.WriteString("rue")
+			t.i++
+		case r == 'ฦ':
+			t.b.WriteString("lue")
+			t.i++
+		case unicode.Is(unicode.Thai, r):
+			t.i++
+		default:
+			t.b.WriteRune(r)
+			t.i++
+		}
+	}
+	return t.b.String()
+}
+
+type thaiTransliterator struct {
+	rs []rune
+	i  int
+	b  strings.Builder
+}
+
+// at returns the rune at j or after the marks that follow it, 0 at the end
+func (t *thaiTransliterator) at(j int) (rune, int) {
+	for j < len(t.rs) && isThaiMark(t.rs[j]) {
+		j++
+	}
+	if j >= len(t.rs) {
+		return 0, j
+	}
+	return t.rs[j], j
+}
+
+// isOnset tells whether the consonant at j starts a syllable, that is a vowel
+// comes after it
+func (t *thaiTransliterator) isOnset(j int) bool {
+	if j >= len(t.rs) || !isThaiConsonant(t.rs[j]) {
+		return false
+	}
+	n, k := t.at(j + 1)
+	switch {
+	case isThaiVowel(n):
+		return true
+	case n == 'อ' && t.rs[j] != 'อ':
+		return !t.isOnset(k)
+	case isThaiCluster(t.rs[j], n):
+		return t.isOnset(k)
+	}
+	return false
+}
+
+func (t *thaiTransliterator) syllable() {
+	var pre rune
+	if r := t.rs[t.i]; r >= 'เ' && r <= 'ไ' {
+		pre = r
+		t.i++
+	}
+
+	c, j := t.at(t.i)
+	if !isThaiConsonant(c) {
+		t.b.WriteString(map[rune]string{'เ': "e", 'แ': "ae", 'โ': "o", 'ใ': "ai", 'ไ': "ai"}[pre])
+		return
+	}
+	t.i = j + 1
+
+	// Clusters like khr, pl and kw, and the silent h and o that lead a sonorant
+	onset := thaiConsonants[c][0]
+	if n, k := t.at(t.i); isThaiConsonant(n) {
+		leads := c == 'ห' && n != 'ว' || c == 'อ' && n == 'ย' || isThaiCluster(c, n)
+		// ไ and ใ take no final, so a cluster after them is always read
+		if leads && (t.isOnset(k) || pre == 'ไ' || pre == 'ใ' || pre != 0 && k+1 < len(t.rs)) {
+			if c == 'ห' || c == 'อ' {
+				onset = ""
+			}
+			onset += thaiConsonants[n][0]
+			t.i = k + 1
+		}
+	}
+	t.b.WriteString(onset)
+
+	vowel, closes := t.vowel(pre)
+	t.b.WriteString(vowel)
+	if !closes {
+		return
+	}
+
+	if n, k := t.at(t.i); isThaiConsonant(n) && !t.isOnset(k) {
+		t.b.WriteString(thaiConsonants[n][1])
+		t.i = k + 1
+
+		// A consonant left over at the end of a word is silent, e.g. ศาสตร์
+		if n, k := t.at(t.i); isThaiConsonant(n) && !t.isOnset(k) {
+			if m, _ := t.at(k + 1); !unicode.Is(unicode.Thai, m) {
+				t.i = k + 1
+			}
+		}
+	}
+}
+
+// vowel reads the vowel after the onset, it tells whether a final consonant
+// can close the syllable
+func (t *thaiTransliterator) vowel(pre rune) (string, bool) {
+	n, j := t.at(t.i)
+	next := func(r rune) bool {
+		if m, k := t.at(j + 1); m == r {
+			j = k
+			return true
+		}
+		return false
+	}
+	take := func(s string, closes bool) (string, bool) {
+		t.i = j + 1
+		if m, k := t.at(t.i); m == 'ะ' {
+			t.i = k + 1
+			return s, false
+		}
+		return s, closes
+	}
+
+	switch pre {
+	case 'เ':
+		switch {
+		case n == 'า':
+			return take("ao", false)
+		case n == 'ี' && next('ย'), n == 'ื' && next('อ'):
+			if n == 'ี' {
+				return take("ia", true)
+			}
+			return take("uea", true)
+		case n == 'ิ', n == 'อ' && !t.isOnset(j):
+			return take("oe", true)
+		case n == 'ะ':
+			return take("e", false)
+		case n == '็':
+			return take("e", true)
+		}
+		return "e", true
+	case 'แ':
+		if n == 'ะ' {
+			return take("ae", false)
+		}
+		if n == '็' {
+			return take("ae", true)
+		}
+		return "ae", true
+	case 'โ':
+		if n == 'ะ' {
+			return take("o", false)
+		}
+		return "o", true
+	case 'ใ', 'ไ':
+		// The y of ไทย is silent
+		if n == 'ย' && !t.isOnset(j) {
+			t.i = j + 1
+		}
+		return "ai", false
+	}
+
+	switch {
+	case n == 'ั' && next('ว'):
+		return take("ua", true)
+	case n == 'ะ':
+		return take("a", false)
+	case n == 'ั', n == 'า':
+		return take("a", true)
+	case n == 'ำ':
+		return take("am", false)
+	case n == 'ิ', n == 'ี':
+		return take("i", true)
+	case n == 'ื' && next('อ'), n == 'ึ', n == 'ื':
+		return take("ue", true)
+	case n == 'ุ', n == 'ู':
+		return take("u", true)
+	case n == 'อ' && !t.isOnset(j):
+		return take("o", true)
+	case n == 'ว' && !t.isOnset(j):
+		if m, k := t.at(j + 1); isThaiConsonant(m) && !t.isOnset(k) {
+			return take("ua", true)
+		}
+	case n == 'ร' && next('ร'):
+		// รร reads an, or a with the final after it
+		t.i = j + 1
+		if m, k := t.at(t.i); isThaiConsonant(m) && !t.isOnset(k) {
+			return "a", true
+		}
+		return "an", false
+	}
+
+	// No vowel is written, a closed syllable reads o and an open one reads a.
+	// The second of three bare consonants opens a syllable the third closes.
+	if isThaiConsonant(n) && !t.isOnset(j) {
+		if m, k := t.at(j + 1); isThaiConsonant(m) && !t.isOnset(k) {
+			if l, h := t.at(k + 1); !isThaiConsonant(l) || t.isOnset(h) {
+				return "a", false
+			}
+		}
+		return "o", true
+	}
+	if isThaiConsonant(n) {
+		return "a", false
+	}
+	return "o", false
+}
@@ -0,0 +1,71 @@
+package utils
+
+import "testing"
+
+func TestTransliterateThai(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"coffee", "coffee"},
+		{"กาแฟ", "kafae"},
+		{"ประเทศไทย", "prathetthai"},
+		{"ศาสตร์", "sat"},
+		{"ขนมปัง", "khanompang"},
+		{"เสื้อ", "suea"},
+		{"ผ้าไหม", "phamai"},
+		{"ชา ๒ แก้ว", "cha 2 kaeo"},
+	}
+
+	for _, tt := range tests {
+		if got := TransliterateThai(tt.in); got != tt.want {
+			t.Errorf("TransliterateThai(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"Coffee", "coffee"},
+		{"  Hot & Iced Coffee!  ", "hot-iced-coffee"},
+		{"Café au lait", "cafe-au-lait"},
+		{"Straße", "strasse"},
+		{"กาแฟ สด", "kafae-sot"},
+		{"日本茶", ""},
+		{"日本茶 Matcha 100g", "matcha-100g"},
+	}
+
+	for _, tt := range tests {
+		if got := Slugify(tt.in); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	tests := []struct {
+		slug  string
+		taken []string
+		want  string
+	}{
+		{"coffee", nil, "coffee"},
+		{"coffee", []string{"tea"}, "coffee"},
+		{"coffee", []string{"coffee"}, "coffee-2"},
+		{"coffee", []string{"coffee", "coffee-2", "coffee-3"}, "coffee-4"},
+		{"coffee", []string{"coffee", "coffee-3"}, "coffee-2"},
+		{"iphone-1", []string{"iphone-1"}, "iphone-1-2"},
+		{"iphone-1", []string{"iphone-1", "iphone-2", "iphone-1-2"}, "iphone-1-3"},
+	}
+
+	for _, tt := range tests {
+		if got := UniqueSlug(tt.slug, tt.taken); got != tt.want {
+			t.Errorf("UniqueSlug(%q, %q) = %q, want %q", tt.slug, tt.taken, got, tt.want)
+		}
+	}
+}